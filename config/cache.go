@@ -0,0 +1,34 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PushToListCache appends results as json strings to a redis list and trims it to the latest maxLength entries,
+// it is shared by the query engines that keep per tick history lists
+func PushToListCache(ctx context.Context, rdb *redis.Client, key string, maxLength int64, results ...interface{}) error {
+	if len(results) == 0 {
+		return nil
+	}
+	values := []interface{}{}
+	for _, result := range results {
+		value, err := json.Marshal(result)
+		if err != nil {
+			return errors.New("PushToListCache: " + err.Error())
+		}
+		values = append(values, string(value))
+	}
+	err := rdb.RPush(ctx, key, values...).Err()
+	if err != nil {
+		return errors.New("PushToListCache: " + err.Error())
+	}
+	err = rdb.LTrim(ctx, key, -maxLength, -1).Err()
+	if err != nil {
+		return errors.New("PushToListCache: " + err.Error())
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -16,14 +17,23 @@ import (
 
 const (
 	BlockNumber         = "BLOCK_NUMBER"
+	ChainStatus         = "CHAIN_STATUS"
+	AverageBlockTime    = "AVERAGE_BLOCK_TIME"
+	BankSupply          = "BANK_SUPPLY"
+	BankDenoms          = "BANK_DENOMS"
+	CommunityPool       = "COMMUNITY_POOL"
 	StakingAPR          = "STAKING_APR"
 	AllValidators       = "ALL_VALIDATORS"
 	ValidatorMap        = "VALIDATOR_MAP"
 	AllCSRs             = "ALL_CSRS"
 	CSRMap              = "CSR_MAP"
+	CSRParams           = "CSR_PARAMS"
+	CSRTurnstile        = "CSR_TURNSTILE"
+	CSRRevenueHistory   = "CSR_REVENUE_HISTORY"
 	AllProposals        = "ALL_PROPOSALS"
 	ProposalMap         = "PROPOSAL_MAP"
 	AllProposalsHttp    = "ALL_PROPOSALS_HTTP"
+	GovParams           = "GOV_PARAMS"
 	Pairs               = "PAIRS"
 	ProcessedPairs      = "PROCESSED_PAIRS"
 	ProcessedPairsMap   = "PROCESSED_PAIRS_MAP"
@@ -31,8 +41,39 @@ const (
 	ProcessedCTokens    = "PROCESSED_CTOKENS"
 	ProcessedCTokensMap = "PROCESSED_CTOKENS_MAP"
 	UserDelegations 	= "USER_DELEGATIONS"
+	ValidatorDelegationsMap = "VALIDATOR_DELEGATIONS_MAP"
+	ValidatorUnbondingsMap  = "VALIDATOR_UNBONDINGS_MAP"
+	ValidatorCommissionMap         = "VALIDATOR_COMMISSION_MAP"
+	ValidatorOutstandingRewardsMap = "VALIDATOR_OUTSTANDING_REWARDS_MAP"
+	ValidatorEvents         = "VALIDATOR_EVENTS"
+	ValidatorSnapshots      = "VALIDATOR_SNAPSHOTS"
+	WebhookSent             = "WEBHOOK_SENT"
+	IndexerHeight           = "INDEXER_HEIGHT"
+	IndexerBlockHash        = "INDEXER_BLOCK_HASH"
+	IndexerEvents           = "INDEXER_EVENTS"
+	PriceFeed               = "PRICE_FEED"
+	TokenPrices             = "TOKEN_PRICES"
+	TokenPricesMap          = "TOKEN_PRICES_MAP"
+	ProtocolStats           = "PROTOCOL_STATS"
+	ProtocolStatsHistory    = "PROTOCOL_STATS_HISTORY"
 )
 
+// maximum number of entries kept in per tick history lists
+const MaxHistoryLength = 10000
+
+// protocol stats history keeps one entry per interval in seconds, up to two years of hourly entries
+const (
+	ProtocolStatsHistoryInterval int64 = 3600
+	ProtocolStatsHistoryLength   int64 = 17520
+)
+
+// the native engine publishes the measured average block time under AverageBlockTime every tick and the
+// contract engine annualizes lending rates with it, the value expires so a stopped native engine isn't read as measured
+const AverageBlockTimeExpiration = 10 * time.Minute
+
+// number of native engine ticks between refreshes of per validator delegations, unbondings, commission and outstanding rewards
+const ValidatorDelegationsRefreshTicks = 10
+
 var (
 	RDB              *redis.Client
 	EthClient        *ethclient.Client
@@ -42,6 +83,9 @@ var (
 	QueryInterval    uint
 	FPIConfig        TokensInfo
 	BackupRpcIndex   int // index of backup rpc url
+	Webhooks         []Webhook
+	IndexerEnabled     bool   // the event indexer only runs when a start height is configured
+	IndexerStartHeight uint64 // block height the event indexer backfills from
 )
 
 /*
@@ -101,6 +145,25 @@ func NewConfig() {
 		log.Fatal().Msgf("Error getting general contracts from json: %v", err)
 	}
 
+	// get webhooks from the optional webhooks json file
+	webhooksFile := os.Getenv("WEBHOOKS_FILE")
+	if webhooksFile != "" {
+		Webhooks, err = getWebhooksFromJson(webhooksFile)
+		if err != nil {
+			log.Fatal().Msgf("Error getting webhooks from json: %v", err)
+		}
+	}
+
+	// set event indexer start height
+	indexerStartHeight := os.Getenv("INDEXER_START_HEIGHT")
+	if indexerStartHeight != "" {
+		IndexerStartHeight, err = strconv.ParseUint(indexerStartHeight, 10, 64)
+		if err != nil {
+			log.Fatal().Msgf("Error converting indexer start height to int: %v", err)
+		}
+		IndexerEnabled = true
+	}
+
 	// set multicall address
 	mcAddress := os.Getenv("MULTICALL_ADDRESS")
 	MulticallAddress = common.HexToAddress(mcAddress)
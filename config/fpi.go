@@ -30,6 +30,10 @@ func getCTokenContractCalls() []Contract {
 				"cTokens:" + token.Address + ":supplyRatePerBlock",
 				"cTokens:" + token.Address + ":borrowRatePerBlock",
 				"cTokens:" + token.Address + ":totalSupply",
+				"cTokens:" + token.Address + ":totalBorrows",
+				"cTokens:" + token.Address + ":totalReserves",
+				"cTokens:" + token.Address + ":reserveFactorMantissa",
+				"cTokens:" + token.Address + ":interestRateModel",
 			},
 			Methods: []string{
 				"getCash()(uint256)",
@@ -37,6 +41,10 @@ func getCTokenContractCalls() []Contract {
 				"supplyRatePerBlock()(uint256)",
 				"borrowRatePerBlock()(uint256)",
 				"totalSupply()(uint256)",
+				"totalBorrows()(uint256)",
+				"totalReserves()(uint256)",
+				"reserveFactorMantissa()(uint256)",
+				"interestRateModel()(address)",
 			},
 			Args: [][]interface{}{
 				{},
@@ -44,6 +52,10 @@ func getCTokenContractCalls() []Contract {
 				{},
 				{},
 				{},
+				{},
+				{},
+				{},
+				{},
 			},
 		})
 
@@ -103,27 +115,6 @@ func getCTokenContractCalls() []Contract {
 				{},
 			},
 		})
-
-		// check token tags and add calls accordingly
-		if token.Tags != nil {
-			for _, tag := range token.Tags {
-				if tag == "hashnote" {
-					calls = append(calls, Contract{
-						Name:    token.Symbol + "latestRoundDetails",
-						Address: "0x1d18c02bC80b1921255E71cF2939C03258d75470",
-						Keys: []string{
-							"cTokens:" + token.Address + ":latestRoundDetails",
-						},
-						Methods: []string{
-							"latestRoundDetails()(uint80,uint256,uint256,uint256,uint256)",
-						},
-						Args: [][]interface{}{
-							{},
-						},
-					})
-				}
-			}
-		}
 	}
 
 	return calls
@@ -9,8 +9,10 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 type TokensInfo struct {
@@ -26,6 +28,29 @@ type TokensInfo struct {
 	CTokens     []Token  `json:"ctokens"`
 	Tokens      []Token  `json:"tokens"`
 	Pairs       []Pair   `json:"pairs"`
+	// yield sources of cTokens keyed by tag, they replace the supply apy of cTokens with the tag
+	YieldSources map[string]YieldSource `json:"yieldSources,omitempty"`
+	// swap fees of stable and volatile pairs
+	SwapFees SwapFees `json:"swapFees"`
+}
+
+// SwapFees are the shares of the input amount of every swap paid as fees to liquidity providers, by pair type
+type SwapFees struct {
+	Stable   float64 `json:"stable"`
+	Volatile float64 `json:"volatile"`
+}
+
+// YieldSource configures how the supply apy of tagged cTokens is computed
+type YieldSource struct {
+	Type string `json:"type"`
+	// address of the oracle reporting the yield
+	Oracle string `json:"oracle,omitempty"`
+	// percent subtracted from the reported apy (fees kept by the issuer)
+	FeeOffset float64 `json:"feeOffset,omitempty"`
+	// constant apy in percent
+	FixedRate float64 `json:"fixedRate,omitempty"`
+	// days of interest covered by a report made on a holiday, keyed by "MM-DD"
+	Holidays map[string]float64 `json:"holidays,omitempty"`
 }
 
 // this stores data of underlying token/pair of ctoken
@@ -46,6 +71,32 @@ type Token struct {
 	ChainID    string   `json:"chainId"`
 	LogoURI    string   `json:"logoURI,omitempty"`
 	Tags       []string `json:"tags,omitempty"`
+	// price served for the token, the oracle price is served if unset
+	PriceSource *PriceSource `json:"priceSource,omitempty"`
+}
+
+// types of price sources
+const (
+	PriceSourceOracle = "oracle"
+	PriceSourceFixed  = "fixed"
+	PriceSourcePair   = "pair"
+	PriceSourceFeed   = "feed"
+)
+
+// fraction the oracle price may diverge from the served price if a price source has no tolerance
+const DefaultDepegTolerance = 0.02
+
+// PriceSource configures where the served price of a token comes from
+type PriceSource struct {
+	Type string `json:"type"`
+	// usd price of a fixed peg
+	Price float64 `json:"price,omitempty"`
+	// address of the pair the price is derived from
+	Pair string `json:"pair,omitempty"`
+	// name of the external feed the price is read from
+	Feed string `json:"feed,omitempty"`
+	// fraction the oracle price may diverge from the served price before the token is flagged as depegged
+	Tolerance float64 `json:"tolerance,omitempty"`
 }
 
 type Pair struct {
@@ -77,9 +128,67 @@ func getFPIFromJson(path string) (TokensInfo, error) {
 		return TokensInfo, errors.New("Config::getFPIFromJson - " + err.Error())
 	}
 
+	err = validatePriceSources(TokensInfo)
+	if err != nil {
+		return TokensInfo, errors.New("Config::getFPIFromJson - " + err.Error())
+	}
+
+	err = validateSwapFees(TokensInfo.SwapFees)
+	if err != nil {
+		return TokensInfo, errors.New("Config::getFPIFromJson - " + err.Error())
+	}
+
 	return TokensInfo, nil
 }
 
+// checks the price sources of cTokens have the fields their type needs
+func validatePriceSources(info TokensInfo) error {
+	for _, cToken := range info.CTokens {
+		source := cToken.PriceSource
+		if source == nil {
+			continue
+		}
+		switch source.Type {
+		case PriceSourceOracle:
+		case PriceSourceFixed:
+			if source.Price <= 0 {
+				return fmt.Errorf("fixed price source of %s has no price", cToken.Symbol)
+			}
+		case PriceSourcePair:
+			found := false
+			for _, pair := range info.Pairs {
+				if strings.EqualFold(pair.Address, source.Pair) && (strings.EqualFold(pair.TokenA, cToken.Underlying) || strings.EqualFold(pair.TokenB, cToken.Underlying)) {
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("pair price source of %s is not a configured pair of its underlying", cToken.Symbol)
+			}
+		case PriceSourceFeed:
+			if source.Feed == "" {
+				return fmt.Errorf("feed price source of %s has no feed", cToken.Symbol)
+			}
+		default:
+			return fmt.Errorf("unknown price source type %q for %s", source.Type, cToken.Symbol)
+		}
+		if source.Tolerance < 0 {
+			return fmt.Errorf("price source of %s has a negative tolerance", cToken.Symbol)
+		}
+	}
+	return nil
+}
+
+// checks the swap fees are shares of the swap input amount
+func validateSwapFees(fees SwapFees) error {
+	if fees.Stable < 0 || fees.Stable >= 1 {
+		return fmt.Errorf("stable swap fee %v is not between 0 and 1", fees.Stable)
+	}
+	if fees.Volatile < 0 || fees.Volatile >= 1 {
+		return fmt.Errorf("volatile swap fee %v is not between 0 and 1", fees.Volatile)
+	}
+	return nil
+}
+
 // this function returns the ctoken address of the token having address equal to underlyingAddress
 func GetCTokenAddress(underlyingAddress string) (cTokenAddress string) {
 	// iterate through ctokens config to get the ctoken address of the token with underlyingAddress
@@ -180,6 +289,24 @@ func GetLpPairData(address string) (symbol string, decimals int64, token1 Token,
 	return
 }
 
+// get swap fee of a stable or volatile pair from tokens config
+func GetPairSwapFee(stable bool) float64 {
+	if stable {
+		return FPIConfig.SwapFees.Stable
+	}
+	return FPIConfig.SwapFees.Volatile
+}
+
+// get price source of ctoken from tokens config using cToken address, nil if the oracle price is served
+func GetCTokenPriceSource(address string) *PriceSource {
+	for _, cToken := range FPIConfig.CTokens {
+		if cToken.Address == address {
+			return cToken.PriceSource
+		}
+	}
+	return nil
+}
+
 // get ctoken data (Symbol, Name, Decimals, Underlying) from tokens config using cToken address
 func GetCTokenData(address string) (symbol string, name string, decimals int64, tags []string, underlying Underlying) {
 	for _, cToken := range FPIConfig.CTokens {
@@ -197,3 +324,25 @@ func GetCTokenData(address string) (symbol string, name string, decimals int64,
 	}
 	return
 }
+
+// get an address as it is written in the tokens config (ctokens, tokens or pairs), ignoring case,
+// so cache keys built from the config can be found from any form of the address.
+// returns the given address if it is not in the config
+func GetConfiguredAddress(address string) string {
+	for _, token := range FPIConfig.CTokens {
+		if strings.EqualFold(token.Address, address) {
+			return token.Address
+		}
+	}
+	for _, token := range FPIConfig.Tokens {
+		if strings.EqualFold(token.Address, address) {
+			return token.Address
+		}
+	}
+	for _, pair := range FPIConfig.Pairs {
+		if strings.EqualFold(pair.Address, address) {
+			return pair.Address
+		}
+	}
+	return address
+}
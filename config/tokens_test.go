@@ -185,3 +185,68 @@ func Test_getFPIFromJson(t *testing.T) {
 		})
 	}
 }
+
+func Test_validatePriceSources(t *testing.T) {
+	pairs := []Pair{
+		{
+			Address: "0x1D20635535307208919f0b67c3B2065965A85aA9",
+			TokenA:  "0x826551890Dc65655a0Aceca109aB11AbDbD7a07B",
+			TokenB:  "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503",
+		},
+	}
+	cToken := func(source *PriceSource) TokensInfo {
+		return TokensInfo{
+			CTokens: []Token{
+				{
+					Symbol:      "cNOTE",
+					Underlying:  "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503",
+					PriceSource: source,
+				},
+			},
+			Pairs: pairs,
+		}
+	}
+	tests := []struct {
+		name    string
+		info    TokensInfo
+		wantErr bool
+	}{
+		{name: "no price source", info: cToken(nil)},
+		{name: "oracle", info: cToken(&PriceSource{Type: PriceSourceOracle})},
+		{name: "fixed", info: cToken(&PriceSource{Type: PriceSourceFixed, Price: 1, Tolerance: 0.02})},
+		{name: "fixed without price", info: cToken(&PriceSource{Type: PriceSourceFixed}), wantErr: true},
+		{name: "pair", info: cToken(&PriceSource{Type: PriceSourcePair, Pair: "0x1d20635535307208919f0b67c3b2065965a85aa9"})},
+		{name: "unknown pair", info: cToken(&PriceSource{Type: PriceSourcePair, Pair: "0x30838619C55B787BafC3A4cD9aEa851C1cfB7b19"}), wantErr: true},
+		{name: "feed", info: cToken(&PriceSource{Type: PriceSourceFeed, Feed: "note"})},
+		{name: "feed without name", info: cToken(&PriceSource{Type: PriceSourceFeed}), wantErr: true},
+		{name: "negative tolerance", info: cToken(&PriceSource{Type: PriceSourceFixed, Price: 1, Tolerance: -1}), wantErr: true},
+		{name: "unknown type", info: cToken(&PriceSource{Type: "twap"}), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePriceSources(tt.info); (err != nil) != tt.wantErr {
+				t.Errorf("validatePriceSources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateSwapFees(t *testing.T) {
+	tests := []struct {
+		name    string
+		fees    SwapFees
+		wantErr bool
+	}{
+		{name: "no fees", fees: SwapFees{}},
+		{name: "fees", fees: SwapFees{Stable: 0.0001, Volatile: 0.003}},
+		{name: "negative stable fee", fees: SwapFees{Stable: -0.0001, Volatile: 0.003}, wantErr: true},
+		{name: "whole volatile fee", fees: SwapFees{Stable: 0.0001, Volatile: 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSwapFees(tt.fees); (err != nil) != tt.wantErr {
+				t.Errorf("validateSwapFees() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// webhook rule types
+const (
+	RuleProposalVoting    = "PROPOSAL_VOTING"    // a proposal enters its voting period
+	RuleValidatorJailed   = "VALIDATOR_JAILED"   // a validator gets jailed, address optionally limits the rule to one validator
+	RuleCTokenUtilization = "CTOKEN_UTILIZATION" // a cToken's utilization rises above threshold percent, address optionally limits the rule to one cToken
+	RulePairTvlDrop       = "PAIR_TVL_DROP"      // a pair's TVL drops by threshold percent from its highest TVL since the last notification, address optionally limits the rule to one pair
+	RuleStakingAPRChange  = "STAKING_APR_CHANGE" // staking APR moves by threshold percentage points since the last notification
+)
+
+var webhookRuleTypes = map[string]bool{
+	RuleProposalVoting:    true,
+	RuleValidatorJailed:   true,
+	RuleCTokenUtilization: true,
+	RulePairTvlDrop:       true,
+	RuleStakingAPRChange:  true,
+}
+
+type Webhook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// secretEnv is the name of the environment variable holding the secret payloads are signed with
+	SecretEnv string        `json:"secretEnv"`
+	Rules     []WebhookRule `json:"rules"`
+}
+
+type WebhookRule struct {
+	Type      string  `json:"type"`
+	Address   string  `json:"address,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// Secret returns the secret payloads to this webhook are signed with
+func (w Webhook) Secret() string {
+	return os.Getenv(w.SecretEnv)
+}
+
+// parses webhooks.json and returns the configured webhooks
+func getWebhooksFromJson(path string) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New("Config::getWebhooksFromJson - " + err.Error())
+	}
+	defer file.Close()
+
+	fileContent, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errors.New("Config::getWebhooksFromJson - " + err.Error())
+	}
+	err = json.Unmarshal(fileContent, &webhooks)
+	if err != nil {
+		return nil, errors.New("Config::getWebhooksFromJson - " + err.Error())
+	}
+
+	names := make(map[string]bool)
+	for _, webhook := range webhooks {
+		if webhook.Name == "" || webhook.URL == "" {
+			return nil, errors.New("Config::getWebhooksFromJson - webhooks need a name and url")
+		}
+		// names are part of the dedupe keys of sent notifications
+		if names[webhook.Name] {
+			return nil, fmt.Errorf("Config::getWebhooksFromJson - duplicate webhook name %s", webhook.Name)
+		}
+		names[webhook.Name] = true
+		for _, rule := range webhook.Rules {
+			if !webhookRuleTypes[rule.Type] {
+				return nil, fmt.Errorf("Config::getWebhooksFromJson - invalid rule type %s for webhook %s", rule.Type, webhook.Name)
+			}
+		}
+	}
+	return webhooks, nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_getWebhooksFromJson(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    []Webhook
+		wantErr bool
+	}{
+		{
+			name: "one webhook with every rule type",
+			args: "./jsons/tests/webhooks_test_01.json",
+			want: []Webhook{
+				{
+					Name:      "ops",
+					URL:       "https://example.com/hooks/ops",
+					SecretEnv: "OPS_WEBHOOK_SECRET",
+					Rules: []WebhookRule{
+						{Type: RuleProposalVoting},
+						{Type: RuleValidatorJailed, Address: "cantovaloper1fec69efhkluajsfa8xgax72ccz67regrvffvvr"},
+						{Type: RuleCTokenUtilization, Address: "0xEe602429Ef7eCe0a13e4FfE8dBC16e101049504C", Threshold: 90},
+						{Type: RulePairTvlDrop, Threshold: 20},
+						{Type: RuleStakingAPRChange, Threshold: 0.5},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "unknown rule type",
+			args:    "./jsons/tests/webhooks_test_02.json",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate webhook names",
+			args:    "./jsons/tests/webhooks_test_03.json",
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			args:    "./jsons/tests/webhooks_test_missing.json",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getWebhooksFromJson(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getWebhooksFromJson() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getWebhooksFromJson() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,138 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/address/{address}": {
+            "get": {
+                "description": "return json object of the account, validator operator and EVM forms of a bech32 or 0x address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Convert an address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "bech32 or 0x address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank/balances/{address}": {
+            "get": {
+                "description": "return json object of all bank balances of an account with their display units, ERC20 pairs and the ERC20 balances of the account's EVM address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query balances by account address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "account address (bech32 or 0x)",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank/denoms": {
+            "get": {
+                "description": "return json array of denom metadata with ERC20 pairs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query denoms",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank/supply": {
+            "get": {
+                "description": "return json array of the total supply of all denoms with their display units and ERC20 pairs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query total supply",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/chain/status": {
+            "get": {
+                "description": "return json object of chain level overview (blocks, inflation, supply, staking and community pool)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query chain status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/csr": {
             "get": {
                 "description": "return json list of CSRs",
@@ -36,6 +168,75 @@ const docTemplate = `{
                 }
             }
         },
+        "/csr/contract/{address}": {
+            "get": {
+                "description": "return json object of the CSR a contract is registered to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query CSR by contract address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "contract address (bech32 or 0x)",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/csr/params": {
+            "get": {
+                "description": "return json object of CSR module params",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query CSR params",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/csr/turnstile": {
+            "get": {
+                "description": "return string of the turnstile contract address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query turnstile address",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/csr/{id}": {
             "get": {
                 "description": "return json object of CSR",
@@ -65,6 +266,35 @@ const docTemplate = `{
                 }
             }
         },
+        "/csr/{id}/revenue": {
+            "get": {
+                "description": "return json list of revenue earned by a CSR NFT per tick",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query CSR revenue history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CSR nft id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/dex/pairs": {
             "get": {
                 "description": "return json array of all pairs in Canto dex",
@@ -105,16 +335,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/gov/proposals": {
+        "/distribution/community-pool": {
             "get": {
-                "description": "return json list of proposals",
+                "description": "return json array of the community pool balance with display units",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Query proposal list",
+                "summary": "Query community pool",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -125,21 +355,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/gov/proposals/{id}": {
+        "/distribution/validators/{address}/commission": {
             "get": {
-                "description": "return json object of proposal",
+                "description": "return json array of the accumulated commission of a validator with display units",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Query proposal by id",
+                "summary": "Query validator commission",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "proposal id",
-                        "name": "id",
+                        "description": "validator address (bech32 or 0x)",
+                        "name": "address",
                         "in": "path",
                         "required": true
                     }
@@ -154,16 +384,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/lending/ctokens": {
+        "/distribution/validators/{address}/outstanding-rewards": {
             "get": {
-                "description": "return json array of all pairs in CLM",
+                "description": "return json array of the outstanding rewards of a validator with display units",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Query all cTokens in CLM",
+                "summary": "Query validator outstanding rewards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "validator address (bech32 or 0x)",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -174,19 +413,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/lending/ctokens/{address}": {
+        "/erc20/token-pairs": {
             "get": {
-                "description": "return json object of cToken",
+                "description": "return json object of a page of erc20 module token pairs with token list metadata, pass next_key (base64) as key to get the next page",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Query cToken by address",
+                "summary": "Query ERC20 token pairs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "base64 encoded next key of the previous page",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of token pairs per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -194,16 +454,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/staking/apr": {
+        "/gov/params": {
             "get": {
-                "description": "return string of current staking APR",
+                "description": "return json object of deposit, voting and tallying params, periods are in seconds and quorum, threshold and veto threshold are in percent",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Query current staking APR",
+                "summary": "Query governance params",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -214,16 +474,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/staking/validators": {
+        "/gov/proposals": {
             "get": {
-                "description": "return json list of validators",
+                "description": "return json list of proposals, tally status percentages, quorum and thresholds are in percent",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Query validator list",
+                "summary": "Query proposal list",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -234,21 +494,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/staking/validators/{address}": {
+        "/gov/proposals/{id}": {
             "get": {
-                "description": "return json object of validator",
+                "description": "return json object of proposal, tally status percentages, quorum and thresholds are in percent",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
-                "summary": "Query validator by address",
+                "summary": "Query proposal by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "validator address",
-                        "name": "address",
+                        "description": "proposal id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -262,6 +522,490 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/gov/proposals/{id}/deposits": {
+            "get": {
+                "description": "return json list of deposits on a proposal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query deposits on a proposal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "proposal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/gov/proposals/{id}/vote/{voter}": {
+            "get": {
+                "description": "return json object of the vote cast by a voter on a proposal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query a voter's vote on a proposal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "proposal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "voter address (bech32 or 0x)",
+                        "name": "voter",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/gov/proposals/{id}/votes": {
+            "get": {
+                "description": "return a page of votes on a proposal and the key of the next page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query votes on a proposal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "proposal id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "base64 next key from the previous page",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of votes per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/lending/ctoken/{address}/rate-curve": {
+            "get": {
+                "description": "return json array of borrow and supply apy sampled from 0 to 100% utilization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query interest rate curve of a cToken",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "cToken address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lending/ctokens": {
+            "get": {
+                "description": "return json array of all pairs in CLM",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query all cTokens in CLM",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/lending/ctokens/{address}": {
+            "get": {
+                "description": "return json object of cToken",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query cToken by address",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices": {
+            "get": {
+                "description": "return json array of usd prices of every token in the token list with their price source, tokens without a price have source \"none\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query usd prices of all tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/{token}": {
+            "get": {
+                "description": "return json object of usd price of a token with its price source",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query usd price of a token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "token address",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/staking/apr": {
+            "get": {
+                "description": "return string of current staking APR, net of community tax",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query current staking APR",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/staking/delegations/{address}": {
+            "get": {
+                "description": "return json object of delegations, unbondings, redelegations, rewards and liquid balance for a given delegator address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query delegations by delegator address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "delegator address (bech32 or 0x)",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/staking/events": {
+            "get": {
+                "description": "return json list of jailing, unjailing, tombstone, status and commission change events of validators, oldest first, height and time are of the block the change was detected at",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query validator events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "validator address (bech32 or 0x)",
+                        "name": "validator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "event type (JAILED, UNJAILED, TOMBSTONED, STATUS_CHANGED, COMMISSION_CHANGED)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/staking/validators": {
+            "get": {
+                "description": "return json list of validators",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query validator list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/staking/validators/{address}": {
+            "get": {
+                "description": "return json object of validator",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query validator by address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "validator address (bech32 or 0x)",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/staking/validators/{address}/delegations": {
+            "get": {
+                "description": "return json list of delegations to a validator, sorted by amount",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query delegations to a validator",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "validator address (bech32 or 0x)",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/staking/validators/{address}/unbondings": {
+            "get": {
+                "description": "return json list of unbonding delegation entries from a validator, sorted by amount",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query unbonding delegations from a validator",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "validator address (bech32 or 0x)",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "return json object of protocol tvl, lending totals and average apys",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query protocol stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/history": {
+            "get": {
+                "description": "return json object of a page of the hourly protocol stats, oldest first, with the total number of entries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "summary": "Query protocol stats history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "index of the first entry of the page",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of entries per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
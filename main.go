@@ -5,6 +5,7 @@ import (
 
 	"althea-api/config"
 	// cqe "althea-api/queryengine/contracts"
+	iqe "althea-api/queryengine/indexer"
 	nqe "althea-api/queryengine/native"
 	re "althea-api/requestengine"
 )
@@ -14,5 +15,6 @@ func main() {
 	ctx := context.Background()
 	// go cqe.Run(ctx) // run contract query engine
 	go nqe.Run(ctx) // run native query engine
+	go iqe.Run(ctx) // run event indexer
 	re.Run(ctx)     // run request engine
 }
@@ -2,9 +2,16 @@ package queryengine
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"time"
 
 	"althea-api/config"
+	indexer "althea-api/queryengine/indexer"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 )
 
 // SetJsonToCache will take key, result and sets the resulte as a json string to redis
@@ -49,41 +56,171 @@ func (qe *QueryEngine) SetCacheWithGeneral(ctx context.Context, results map[stri
 	return nil
 }
 
+// get the average block time and whether it was measured. The contract engine doesn't measure block time itself,
+// it reads the average the native engine publishes every tick. DefaultSecondsPerBlock is returned if the native
+// engine isn't running or its measurement has expired.
+func (qe *QueryEngine) GetSecondsPerBlock(ctx context.Context) (float64, bool) {
+	secondsPerBlock, err := qe.redisclient.Get(ctx, config.AverageBlockTime).Float64()
+	if err != nil || secondsPerBlock <= 0 {
+		log.Warn().Err(err).Str("func", "GetSecondsPerBlock").Msgf("no measured block time, using default of %.2f seconds", DefaultSecondsPerBlock)
+		return DefaultSecondsPerBlock, false
+	}
+	return secondsPerBlock, true
+}
+
+// GetPairSwaps gets the swaps indexed for each pair over the last 7 days of blocks,
+// pairs have no swaps if the event indexer is disabled
+func (qe *QueryEngine) GetPairSwaps(ctx context.Context, blocknumber string, pairs PairsMap) (map[string][]indexer.IndexedEvent, error) {
+	swaps := make(map[string][]indexer.IndexedEvent)
+	if !config.IndexerEnabled {
+		return swaps, nil
+	}
+	latest, err := strconv.ParseUint(blocknumber, 10, 64)
+	if err != nil {
+		return nil, errors.New("GetPairSwaps: " + err.Error())
+	}
+	// blocks are filtered by timestamp later, so the window is widened to cover slower blocks
+	secondsPerBlock, _ := qe.GetSecondsPerBlock(ctx)
+	window := uint64(BlocksPerDay(secondsPerBlock) * 7 * 1.2)
+	var from uint64
+	if latest > window {
+		from = latest - window
+	}
+	for address := range pairs {
+		events, err := indexer.GetNamedEvents(ctx, address, "Swap", from, latest)
+		if err != nil {
+			return nil, errors.New("GetPairSwaps: " + err.Error())
+		}
+		swaps[address] = events
+	}
+	return swaps, nil
+}
+
+// get the time of the last protocol stats history entry, read from cache after a restart
+func (qe *QueryEngine) getLastStatsHistoryTime(ctx context.Context) (int64, error) {
+	if qe.lastStatsHistory > 0 {
+		return qe.lastStatsHistory, nil
+	}
+	val, err := qe.redisclient.LIndex(ctx, config.ProtocolStatsHistory, -1).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var last ProtocolStats
+	err = json.Unmarshal([]byte(val), &last)
+	if err != nil {
+		return 0, err
+	}
+	qe.lastStatsHistory = last.Time
+	return last.Time, nil
+}
+
 // This function gets the pairs data from redis, processes it and sets the processed pairs data to redis
-func (qe *QueryEngine) SetCacheWithProcessedPairs(ctx context.Context, blocknumber string, pairs PairsMap) error {
+// the processed pairs are returned so rules can be evaluated over them
+func (qe *QueryEngine) SetCacheWithProcessedPairs(ctx context.Context, blocknumber string, pairs PairsMap) ([]ProcessedPair, error) {
+	// get swaps of the last 7 days from the event indexer
+	swaps, err := qe.GetPairSwaps(ctx, blocknumber, pairs)
+	if err != nil {
+		return nil, errors.New("SetCacheWithProcessedPairs: " + err.Error())
+	}
+
 	// get processed pairs data
-	processedPairs, processedPairsMap := GetProcessedPairs(ctx, blocknumber, pairs)
+	processedPairs, processedPairsMap := GetProcessedPairs(ctx, blocknumber, pairs, swaps, uint64(time.Now().Unix()))
 
 	// set processed pairs as a json string to redis
-	err := qe.SetJsonToCache(ctx, config.ProcessedPairs, blocknumber, processedPairs)
+	err = qe.SetJsonToCache(ctx, config.ProcessedPairs, blocknumber, processedPairs)
 	if err != nil {
-		return errors.New("SetCacheWithProcessedPairs: " + err.Error())
+		return nil, errors.New("SetCacheWithProcessedPairs: " + err.Error())
 	}
 
 	// set processed pairs map as a json string to redis
 	err = qe.SetMapToCache(ctx, config.ProcessedPairsMap, processedPairsMap)
 	if err != nil {
-		return errors.New("SetCacheWithProcessedPairs: " + err.Error())
+		return nil, errors.New("SetCacheWithProcessedPairs: " + err.Error())
 	}
 
-	return nil
+	return processedPairs, nil
 }
 
-func (qe *QueryEngine) SetCacheWithProcessedCTokens(ctx context.Context, blocknumber string, ctokens TokensMap) error {
-	// get processed ctokens data
-	processedCTokens, processedCTokensMap := GetProcessedCTokens(ctx, ctokens)
+// processed pairs are used to derive prices of cTokens priced from a pair, the processed cTokens are returned
+func (qe *QueryEngine) SetCacheWithProcessedCTokens(ctx context.Context, blocknumber string, ctokens TokensMap, processedPairs []ProcessedPair) ([]ProcessedCToken, error) {
+	// get inputs of the price sources
+	feeds, err := qe.GetPriceFeeds(ctx)
+	if err != nil {
+		return nil, errors.New("SetCacheWithProcessedCTokens: " + err.Error())
+	}
+	prices := PriceInputs{Pairs: make(map[string]ProcessedPair), Feeds: feeds}
+	for _, pair := range processedPairs {
+		prices.Pairs[pair.Address] = pair
+	}
+
+	// get processed ctokens data, annualized with the measured block time
+	rateModels := qe.GetInterestRateModels(ctx, ctokens)
+	secondsPerBlock, measured := qe.GetSecondsPerBlock(ctx)
+	processedCTokens, processedCTokensMap := GetProcessedCTokens(ctx, ctokens, secondsPerBlock, measured, rateModels, qe.yieldSources, prices)
 
 	// set processed ctokens as a json string to redis
-	err := qe.SetJsonToCache(ctx, config.ProcessedCTokens, blocknumber, processedCTokens)
+	err = qe.SetJsonToCache(ctx, config.ProcessedCTokens, blocknumber, processedCTokens)
 	if err != nil {
-		return errors.New("SetCacheWithProcessedCTokens: " + err.Error())
+		return nil, errors.New("SetCacheWithProcessedCTokens: " + err.Error())
 	}
 
 	// set processed ctokens map as a json string to redis
 	err = qe.SetMapToCache(ctx, config.ProcessedCTokensMap, processedCTokensMap)
 	if err != nil {
-		return errors.New("SetCacheWithProcessedCTokens: " + err.Error())
+		return nil, errors.New("SetCacheWithProcessedCTokens: " + err.Error())
+	}
+
+	return processedCTokens, nil
+}
+
+// This function prices the tokens in the token list from the processed cTokens and pairs and sets them to redis
+func (qe *QueryEngine) SetCacheWithTokenPrices(ctx context.Context, blocknumber string, processedCTokens []ProcessedCToken, processedPairs []ProcessedPair) error {
+	tokenPrices, tokenPricesMap := GetTokenPrices(config.FPIConfig.Tokens, processedCTokens, processedPairs)
+
+	// set token prices as a json string to redis
+	err := qe.SetJsonToCache(ctx, config.TokenPrices, blocknumber, tokenPrices)
+	if err != nil {
+		return errors.New("SetCacheWithTokenPrices: " + err.Error())
+	}
+
+	// set token prices map to redis, HSet fails without fields
+	if len(tokenPricesMap) == 0 {
+		return nil
+	}
+	err = qe.SetMapToCache(ctx, config.TokenPricesMap, tokenPricesMap)
+	if err != nil {
+		return errors.New("SetCacheWithTokenPrices: " + err.Error())
+	}
+
+	return nil
+}
+
+// This function aggregates the processed cTokens and pairs, sets the stats to redis and appends them to the stats history
+func (qe *QueryEngine) SetCacheWithProtocolStats(ctx context.Context, blocknumber string, processedCTokens []ProcessedCToken, processedPairs []ProcessedPair) error {
+	stats := GetProtocolStats(blocknumber, time.Now().Unix(), processedCTokens, processedPairs)
+
+	// set stats as a json string to redis
+	err := qe.SetJsonToCache(ctx, config.ProtocolStats, blocknumber, stats)
+	if err != nil {
+		return errors.New("SetCacheWithProtocolStats: " + err.Error())
+	}
+
+	// keep one entry per history interval to plot protocol tvl
+	lastTime, err := qe.getLastStatsHistoryTime(ctx)
+	if err != nil {
+		return errors.New("SetCacheWithProtocolStats: " + err.Error())
+	}
+	if !StatsHistoryDue(lastTime, stats.Time, config.ProtocolStatsHistoryInterval) {
+		return nil
+	}
+	err = config.PushToListCache(ctx, qe.redisclient, config.ProtocolStatsHistory, config.ProtocolStatsHistoryLength, stats)
+	if err != nil {
+		return errors.New("SetCacheWithProtocolStats: " + err.Error())
 	}
+	qe.lastStatsHistory = stats.Time
 
 	return nil
 }
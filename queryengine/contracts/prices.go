@@ -0,0 +1,225 @@
+package queryengine
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"althea-api/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PriceInputs holds the data price sources derive prices from
+type PriceInputs struct {
+	// processed pairs keyed by address
+	Pairs map[string]ProcessedPair
+	// usd prices keyed by feed name
+	Feeds map[string]float64
+}
+
+// ScaledPrice converts a usd price to an oracle price scaled by 1e(36-decimals)
+func ScaledPrice(usd float64, decimals int64) *big.Int {
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(36-decimals), nil))
+	price, _ := new(big.Float).Mul(big.NewFloat(usd), scale).Int(nil)
+	return price
+}
+
+// PairDerivedPrice prices a token from the reserves of a pair and the oracle price of the other token in it,
+// prices are scaled by 1e(36-decimals)
+func PairDerivedPrice(pair ProcessedPair, token string) (*big.Int, error) {
+	reserve1, ok1 := new(big.Int).SetString(pair.Reserve1, 10)
+	reserve2, ok2 := new(big.Int).SetString(pair.Reserve2, 10)
+	price1, ok3 := new(big.Int).SetString(pair.Price1, 10)
+	price2, ok4 := new(big.Int).SetString(pair.Price2, 10)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil, errors.New("PairDerivedPrice: invalid pair data")
+	}
+
+	var reserve, otherReserve, otherPrice *big.Int
+	switch {
+	case strings.EqualFold(pair.Token1.Address, token):
+		reserve, otherReserve, otherPrice = reserve1, reserve2, price2
+	case strings.EqualFold(pair.Token2.Address, token):
+		reserve, otherReserve, otherPrice = reserve2, reserve1, price1
+	default:
+		return nil, errors.New("PairDerivedPrice: token is not in pair " + pair.Address)
+	}
+	if reserve.Sign() == 0 {
+		return nil, errors.New("PairDerivedPrice: pair " + pair.Address + " has no reserves")
+	}
+	// value of the other side divided by the amount of the token
+	price := new(big.Int).Mul(otherReserve, otherPrice)
+	return price.Quo(price, reserve), nil
+}
+
+// IsDepegged checks if the oracle price diverges from the served price by more than tolerance
+func IsDepegged(oraclePrice *big.Int, servedPrice *big.Int, tolerance float64) bool {
+	if servedPrice.Sign() == 0 {
+		return oraclePrice.Sign() != 0
+	}
+	difference := new(big.Float).SetInt(new(big.Int).Abs(new(big.Int).Sub(oraclePrice, servedPrice)))
+	divergence, _ := difference.Quo(difference, new(big.Float).SetInt(servedPrice)).Float64()
+	return divergence > tolerance
+}
+
+// ResolvePrice returns the price served for a token, the type of the source it came from and if its oracle
+// price has depegged from it. The oracle price is served if the token has no price source or its source has
+// no price yet, the error reports why the configured source wasn't used.
+func ResolvePrice(source *config.PriceSource, underlying string, decimals int64, oraclePrice *big.Int, inputs PriceInputs) (*big.Int, string, bool, error) {
+	if source == nil {
+		return oraclePrice, config.PriceSourceOracle, false, nil
+	}
+
+	var price *big.Int
+	switch source.Type {
+	case config.PriceSourceOracle:
+		return oraclePrice, config.PriceSourceOracle, false, nil
+	case config.PriceSourceFixed:
+		price = ScaledPrice(source.Price, decimals)
+	case config.PriceSourcePair:
+		pair, ok := inputs.Pairs[config.GetConfiguredAddress(source.Pair)]
+		if !ok {
+			return oraclePrice, config.PriceSourceOracle, false, errors.New("ResolvePrice: pair " + source.Pair + " not found")
+		}
+		derived, err := PairDerivedPrice(pair, underlying)
+		if err != nil {
+			return oraclePrice, config.PriceSourceOracle, false, errors.New("ResolvePrice: " + err.Error())
+		}
+		price = derived
+	case config.PriceSourceFeed:
+		usd, ok := inputs.Feeds[source.Feed]
+		if !ok {
+			return oraclePrice, config.PriceSourceOracle, false, errors.New("ResolvePrice: feed " + source.Feed + " has no price")
+		}
+		price = ScaledPrice(usd, decimals)
+	default:
+		return oraclePrice, config.PriceSourceOracle, false, errors.New("ResolvePrice: unknown price source " + source.Type)
+	}
+
+	tolerance := source.Tolerance
+	if tolerance == 0 {
+		tolerance = config.DefaultDepegTolerance
+	}
+	return price, source.Type, IsDepegged(oraclePrice, price, tolerance), nil
+}
+
+// GetPriceFeeds reads the usd prices external feeds write to redis for every feed in the token list
+func (qe *QueryEngine) GetPriceFeeds(ctx context.Context) (map[string]float64, error) {
+	feeds := make(map[string]float64)
+	for _, cToken := range config.FPIConfig.CTokens {
+		if cToken.PriceSource == nil || cToken.PriceSource.Type != config.PriceSourceFeed {
+			continue
+		}
+		val, err := qe.redisclient.Get(ctx, config.PriceFeed+":"+cToken.PriceSource.Feed).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, errors.New("GetPriceFeeds: " + err.Error())
+		}
+		price, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, errors.New("GetPriceFeeds: invalid price for feed " + cToken.PriceSource.Feed)
+		}
+		feeds[cToken.PriceSource.Feed] = price
+	}
+	return feeds, nil
+}
+
+// source of tokens no source has a price for
+const PriceSourceNone = "none"
+
+// returns the usd price of a token from a price scaled by 1e(36-decimals)
+func formatTokenPrice(price *big.Int, decimals int64) string {
+	return strconv.FormatFloat(FormatUnits(price, 36-decimals), 'f', -1, 64)
+}
+
+// GetTokenPrices prices every token in the token list. Tokens are priced from their cToken, then from the oracle
+// prices of pairs they are in, and finally from the reserves of a pair against a pegged token.
+// Tokens no source has a price for are returned with an empty price and source "none".
+func GetTokenPrices(tokens []config.Token, cTokens []ProcessedCToken, pairs []ProcessedPair) ([]TokenPrice, map[string]string) {
+	tokenPrices := []TokenPrice{}
+	tokenPricesMap := make(map[string]string)
+
+	for _, token := range tokens {
+		tokenPrice := getTokenPrice(token, cTokens, pairs)
+		tokenPrices = append(tokenPrices, tokenPrice)
+		tokenPricesMap[token.Address] = ResultToString(tokenPrice)
+	}
+	return tokenPrices, tokenPricesMap
+}
+
+// prices a token from the first source that has a price for it
+func getTokenPrice(token config.Token, cTokens []ProcessedCToken, pairs []ProcessedPair) TokenPrice {
+	tokenPrice := TokenPrice{
+		Address:  token.Address,
+		Symbol:   token.Symbol,
+		Decimals: token.Decimals,
+	}
+
+	// price served for the cToken
+	for _, cToken := range cTokens {
+		price, ok := new(big.Int).SetString(cToken.Price, 10)
+		if ok && price.Sign() > 0 && strings.EqualFold(cToken.Underlying.Address, token.Address) {
+			tokenPrice.Price = formatTokenPrice(price, token.Decimals)
+			tokenPrice.Source = cToken.PriceSource
+			return tokenPrice
+		}
+	}
+
+	// oracle price of the token in a pair
+	for _, pair := range pairs {
+		var oraclePrice string
+		if strings.EqualFold(pair.Token1.Address, token.Address) {
+			oraclePrice = pair.Price1
+		} else if strings.EqualFold(pair.Token2.Address, token.Address) {
+			oraclePrice = pair.Price2
+		} else {
+			continue
+		}
+		price, ok := new(big.Int).SetString(oraclePrice, 10)
+		if ok && price.Sign() > 0 {
+			tokenPrice.Price = formatTokenPrice(price, token.Decimals)
+			tokenPrice.Source = config.PriceSourceOracle
+			tokenPrice.Pair = pair.Address
+			return tokenPrice
+		}
+	}
+
+	// reserves of a pair against a token with a pegged price
+	for _, pair := range pairs {
+		var other string
+		if strings.EqualFold(pair.Token1.Address, token.Address) {
+			other = pair.Token2.Address
+		} else if strings.EqualFold(pair.Token2.Address, token.Address) {
+			other = pair.Token1.Address
+		} else {
+			continue
+		}
+		if !isPegged(other, cTokens) {
+			continue
+		}
+		price, err := PairDerivedPrice(pair, token.Address)
+		if err == nil && price.Sign() > 0 {
+			tokenPrice.Price = formatTokenPrice(price, token.Decimals)
+			tokenPrice.Source = config.PriceSourcePair
+			tokenPrice.Pair = pair.Address
+			return tokenPrice
+		}
+	}
+	tokenPrice.Source = PriceSourceNone
+	return tokenPrice
+}
+
+// checks if a token is the underlying of a cToken priced at a fixed peg
+func isPegged(token string, cTokens []ProcessedCToken) bool {
+	for _, cToken := range cTokens {
+		if strings.EqualFold(cToken.Underlying.Address, token) && cToken.PriceSource == config.PriceSourceFixed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package queryengine
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"althea-api/config"
+)
+
+// 1 usd for an 18 decimal token and for a 6 decimal token scaled by 1e(36-decimals)
+var (
+	oneUsd18 = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	oneUsd6  = new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)
+)
+
+func TestScaledPrice(t *testing.T) {
+	if got := ScaledPrice(1, 18); got.Cmp(oneUsd18) != 0 {
+		t.Errorf("ScaledPrice() = %v, want %v", got, oneUsd18)
+	}
+	if got := ScaledPrice(1, 6); got.Cmp(oneUsd6) != 0 {
+		t.Errorf("ScaledPrice() = %v, want %v", got, oneUsd6)
+	}
+}
+
+func TestPairDerivedPrice(t *testing.T) {
+	// 1000 NOTE (18 decimals) against 500 tokens with 6 decimals, so the 6 decimal token is worth 2 usd
+	pair := ProcessedPair{
+		Address:  "0x1D20635535307208919f0b67c3B2065965A85aA9",
+		Token1:   config.Token{Address: "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503"},
+		Token2:   config.Token{Address: "0x80b5a32E4F032B2a058b4F29EC95EEfEEB87aDcd"},
+		Reserve1: "1000000000000000000000",
+		Reserve2: "500000000",
+		Price1:   oneUsd18.String(),
+		Price2:   oneUsd6.String(),
+	}
+	tests := []struct {
+		name    string
+		token   string
+		want    *big.Int
+		wantErr bool
+	}{
+		{name: "second token", token: "0x80b5a32e4f032b2a058b4f29ec95eefeeb87adcd", want: new(big.Int).Mul(big.NewInt(2), oneUsd6)},
+		{name: "first token", token: "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503", want: new(big.Int).Quo(oneUsd18, big.NewInt(2))},
+		{name: "token not in pair", token: "0xd567B3d7B8FE3C79a1AD8dA978812cfC4Fa05e75", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PairDerivedPrice(pair, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PairDerivedPrice() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.Cmp(tt.want) != 0 {
+				t.Errorf("PairDerivedPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDepegged(t *testing.T) {
+	tests := []struct {
+		name   string
+		oracle *big.Int
+		want   bool
+	}{
+		{name: "on peg", oracle: oneUsd18, want: false},
+		{name: "within tolerance", oracle: ScaledPrice(0.99, 18), want: false},
+		{name: "below tolerance", oracle: ScaledPrice(0.97, 18), want: true},
+		{name: "above tolerance", oracle: ScaledPrice(1.03, 18), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDepegged(tt.oracle, oneUsd18, 0.02); got != tt.want {
+				t.Errorf("IsDepegged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePrice(t *testing.T) {
+	oracle := ScaledPrice(0.95, 6)
+	inputs := PriceInputs{Feeds: map[string]float64{"usdc": 0.96}}
+	tests := []struct {
+		name         string
+		source       *config.PriceSource
+		want         *big.Int
+		wantSource   string
+		wantDepegged bool
+		wantErr      bool
+	}{
+		{name: "no source", source: nil, want: oracle, wantSource: config.PriceSourceOracle},
+		{name: "oracle", source: &config.PriceSource{Type: config.PriceSourceOracle}, want: oracle, wantSource: config.PriceSourceOracle},
+		{name: "fixed peg", source: &config.PriceSource{Type: config.PriceSourceFixed, Price: 1}, want: oneUsd6, wantSource: config.PriceSourceFixed, wantDepegged: true},
+		{name: "fixed peg with wide tolerance", source: &config.PriceSource{Type: config.PriceSourceFixed, Price: 1, Tolerance: 0.1}, want: oneUsd6, wantSource: config.PriceSourceFixed},
+		{name: "feed", source: &config.PriceSource{Type: config.PriceSourceFeed, Feed: "usdc"}, want: ScaledPrice(0.96, 6), wantSource: config.PriceSourceFeed},
+		{name: "missing feed", source: &config.PriceSource{Type: config.PriceSourceFeed, Feed: "usdt"}, want: oracle, wantSource: config.PriceSourceOracle, wantErr: true},
+		{name: "missing pair", source: &config.PriceSource{Type: config.PriceSourcePair, Pair: "0x1D20635535307208919f0b67c3B2065965A85aA9"}, want: oracle, wantSource: config.PriceSourceOracle, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, source, depegged, err := ResolvePrice(tt.source, "0x80b5a32E4F032B2a058b4F29EC95EEfEEB87aDcd", 6, oracle, inputs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResolvePrice() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.Cmp(tt.want) != 0 || source != tt.wantSource || depegged != tt.wantDepegged {
+				t.Errorf("ResolvePrice() = %v, %v, %v, want %v, %v, %v", got, source, depegged, tt.want, tt.wantSource, tt.wantDepegged)
+			}
+		})
+	}
+}
+
+func TestGetTokenPrices(t *testing.T) {
+	note := config.Token{Address: "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503", Symbol: "NOTE", Decimals: 18}
+	atom := config.Token{Address: "0xecEEEfCEE421D8062EF8d6b4D814efe4dc898265", Symbol: "ATOM", Decimals: 6}
+	wcanto := config.Token{Address: "0x826551890Dc65655a0Aceca109aB11AbDbD7a07B", Symbol: "WCANTO", Decimals: 18}
+	eth := config.Token{Address: "0x5FD55A1B9FC24967C4dB09C513C3BA0DFa7FF687", Symbol: "ETH", Decimals: 18}
+	unpriced := config.Token{Address: "0xd567B3d7B8FE3C79a1AD8dA978812cfC4Fa05e75", Symbol: "UNPRICED", Decimals: 6}
+
+	cTokens := []ProcessedCToken{
+		{Underlying: config.Underlying{Address: note.Address}, Price: oneUsd18.String(), PriceSource: config.PriceSourceFixed},
+		{Underlying: config.Underlying{Address: atom.Address}, Price: ScaledPrice(10, 6).String(), PriceSource: config.PriceSourceOracle},
+	}
+	pairs := []ProcessedPair{
+		// canto has an oracle price in its pair with note
+		{
+			Address:  "0x1D20635535307208919f0b67c3B2065965A85aA9",
+			Token1:   wcanto,
+			Token2:   note,
+			Reserve1: "1000000000000000000000",
+			Reserve2: "200000000000000000000",
+			Price1:   ScaledPrice(0.2, 18).String(),
+			Price2:   oneUsd18.String(),
+		},
+		// eth has no oracle price and is derived from its reserves against note
+		{
+			Address:  "0xf0cd6b5cE8A01D1B81F1d8B76643866c5816b49F",
+			Token1:   note,
+			Token2:   eth,
+			Reserve1: "2000000000000000000000",
+			Reserve2: "1000000000000000000",
+			Price1:   oneUsd18.String(),
+			Price2:   "0",
+		},
+	}
+
+	got, gotMap := GetTokenPrices([]config.Token{note, atom, wcanto, eth, unpriced}, cTokens, pairs)
+	want := []TokenPrice{
+		{Address: note.Address, Symbol: "NOTE", Decimals: 18, Price: "1", Source: config.PriceSourceFixed},
+		{Address: atom.Address, Symbol: "ATOM", Decimals: 6, Price: "10", Source: config.PriceSourceOracle},
+		{Address: wcanto.Address, Symbol: "WCANTO", Decimals: 18, Price: "0.2", Source: config.PriceSourceOracle, Pair: "0x1D20635535307208919f0b67c3B2065965A85aA9"},
+		{Address: eth.Address, Symbol: "ETH", Decimals: 18, Price: "2000", Source: config.PriceSourcePair, Pair: "0xf0cd6b5cE8A01D1B81F1d8B76643866c5816b49F"},
+		{Address: unpriced.Address, Symbol: "UNPRICED", Decimals: 6, Source: PriceSourceNone},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTokenPrices() = %v, want %v", got, want)
+	}
+	if len(gotMap) != len(want) {
+		t.Errorf("GetTokenPrices() map has %d entries, want %d", len(gotMap), len(want))
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"althea-api/config"
 	"althea-api/multicall"
+	"althea-api/webhooks"
+	"althea-api/yieldsources"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
@@ -25,6 +27,17 @@ type QueryEngine struct {
 	mcinstance  *multicall.Multicall
 	viewcalls   multicall.ViewCalls
 	blockkey    string
+	webhooks    *webhooks.Dispatcher
+	// utilization of each cToken at the previous tick
+	utilization map[string]float64
+	// highest TVL of each pair since the last notification, keyed by webhook name then pair address
+	pairTvlBaselines map[string]map[string]float64
+	// interest rate models keyed by address
+	rateModels map[string]*InterestRateModel
+	// yield sources keyed by cToken tag
+	yieldSources map[string]yieldsources.YieldSource
+	// time of the last protocol stats history entry
+	lastStatsHistory int64
 }
 
 // Returns a QueryEngine instance with all necessary objects for
@@ -35,17 +48,29 @@ func NewQueryEngine() *QueryEngine {
 		contractQueryEngineFatalLog(err, "NewQueryEngine", "failed to create multicall instance")
 	}
 
-	vcs, err := ProcessContractCalls(config.ContractCalls)
+	sources, err := yieldsources.NewFromConfig(config.FPIConfig.YieldSources)
+	if err != nil {
+		contractQueryEngineFatalLog(err, "NewQueryEngine", "failed to create yield sources")
+	}
+
+	// add the calls yield sources need to a copy of the contract calls, so the global slice is never written to
+	calls := append(append([]config.Contract{}, config.ContractCalls...), yieldsources.Calls(sources, config.FPIConfig.CTokens)...)
+	vcs, err := ProcessContractCalls(calls)
 	if err != nil {
 		contractQueryEngineFatalLog(err, "NewQueryEngine", "failed to process contract calls")
 	}
 
 	return &QueryEngine{
-		redisclient: config.RDB,
-		interval:    time.Duration(config.QueryInterval),
-		mcinstance:  mc,
-		viewcalls:   vcs,
-		blockkey:    config.BlockNumber,
+		redisclient:      config.RDB,
+		interval:         time.Duration(config.QueryInterval),
+		mcinstance:       mc,
+		viewcalls:        vcs,
+		blockkey:         config.BlockNumber,
+		webhooks:         webhooks.NewDispatcher(),
+		utilization:      make(map[string]float64),
+		pairTvlBaselines: make(map[string]map[string]float64),
+		rateModels:       make(map[string]*InterestRateModel),
+		yieldSources:     sources,
 	}
 }
 
@@ -198,16 +223,30 @@ if err != nil {
 		}
 
 		// process pairs data and set to redis
-		err = qe.SetCacheWithProcessedPairs(ctx, blocknumber, pairs)
+		processedPairs, err := qe.SetCacheWithProcessedPairs(ctx, blocknumber, pairs)
 		if err != nil {
 			contractQueryEngineFatalLog(err, "StartContractQueryEngine", "failed to set processed pairs to redis cache")
 		}
+		qe.notifyPairTvlDrop(ctx, blocknumber, processedPairs)
 
 		// process ctokens data and set to redis
-		err = qe.SetCacheWithProcessedCTokens(ctx, blocknumber, ctokens)
+		processedCTokens, err := qe.SetCacheWithProcessedCTokens(ctx, blocknumber, ctokens, processedPairs)
 		if err != nil {
 			contractQueryEngineFatalLog(err, "StartContractQueryEngine", "failed to set processed ctokens to redis cache")
 		}
+		qe.notifyCTokenUtilization(ctx, blocknumber, ctokens)
+
+		// price tokens and set to redis
+		err = qe.SetCacheWithTokenPrices(ctx, blocknumber, processedCTokens, processedPairs)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to set token prices to redis cache")
+		}
+
+		// aggregate protocol stats and set to redis
+		err = qe.SetCacheWithProtocolStats(ctx, blocknumber, processedCTokens, processedPairs)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to set protocol stats to redis cache")
+		}
 		log.Info().Msg("successfully queried contracts...")
 	}
 }
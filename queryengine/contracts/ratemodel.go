@@ -0,0 +1,190 @@
+package queryengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"althea-api/multicall"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
+)
+
+// methods of compound's jump rate model, in the order of the InterestRateModel fields they fill
+var rateModelMethods = []string{"baseRatePerBlock", "multiplierPerBlock", "jumpMultiplierPerBlock", "kink"}
+
+// ErrNotJumpRateModel is returned for rate models that revert or don't return the jump rate model parameters
+var ErrNotJumpRateModel = errors.New("not a jump rate model")
+
+// InterestRateModel holds the parameters of a jump rate model, rates and kink are scaled by 1e18
+type InterestRateModel struct {
+	Address                string `json:"address"`
+	BaseRatePerBlock       string `json:"baseRatePerBlock"`
+	MultiplierPerBlock     string `json:"multiplierPerBlock"`
+	JumpMultiplierPerBlock string `json:"jumpMultiplierPerBlock"`
+	Kink                   string `json:"kink"`
+}
+
+// utilization step in percent between points of a rate curve
+const RateCurveStep int64 = 5
+
+// RateCurvePoint is the borrow and supply rate of a market at a utilization
+type RateCurvePoint struct {
+	Utilization string `json:"utilization"`
+	BorrowApy   string `json:"borrowApy"`
+	SupplyApy   string `json:"supplyApy"`
+}
+
+// rateModelViewCalls returns the multicall view calls for the parameters of a jump rate model
+func rateModelViewCalls(address string) multicall.ViewCalls {
+	viewcalls := multicall.ViewCalls{}
+	for _, method := range rateModelMethods {
+		viewcalls = append(viewcalls, multicall.NewViewCall(method, address, method+"()(uint256)", []interface{}{}))
+	}
+	return viewcalls
+}
+
+// isExecutionReverted reports whether a call failed because the contract reverted,
+// nodes return the revert data with the error or only the revert message
+func isExecutionReverted(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return true
+	}
+	return strings.Contains(err.Error(), vm.ErrExecutionReverted.Error())
+}
+
+// GetInterestRateModel queries the parameters of a jump rate model through the multicall contract.
+// ErrNotJumpRateModel is returned if the model reverts or its results don't decode,
+// any other error comes from the rpc and the model can be queried again.
+func (qe *QueryEngine) GetInterestRateModel(ctx context.Context, address string) (InterestRateModel, error) {
+	viewcalls := rateModelViewCalls(address)
+	calldata, err := GetCallData(viewcalls)
+	if err != nil {
+		return InterestRateModel{}, errors.New("GetInterestRateModel: " + err.Error())
+	}
+	res, err := qe.mcinstance.Aggregate(&bind.CallOpts{Context: ctx}, calldata)
+	if err != nil {
+		if isExecutionReverted(err) {
+			return InterestRateModel{}, fmt.Errorf("GetInterestRateModel: %w: %s", ErrNotJumpRateModel, err.Error())
+		}
+		return InterestRateModel{}, errors.New("GetInterestRateModel: " + err.Error())
+	}
+	ret, err := viewcalls.Decode(res)
+	if err != nil {
+		return InterestRateModel{}, fmt.Errorf("GetInterestRateModel: %w: %s", ErrNotJumpRateModel, err.Error())
+	}
+	values := make([]string, len(rateModelMethods))
+	for index, method := range rateModelMethods {
+		result := ret.Calls[method]
+		if len(result) != 1 {
+			return InterestRateModel{}, fmt.Errorf("GetInterestRateModel: %w: no result for %s", ErrNotJumpRateModel, method)
+		}
+		values[index], err = InterfaceToString(result[0])
+		if err != nil {
+			return InterestRateModel{}, fmt.Errorf("GetInterestRateModel: %w: %s", ErrNotJumpRateModel, err.Error())
+		}
+	}
+	return InterestRateModel{
+		Address:                address,
+		BaseRatePerBlock:       values[0],
+		MultiplierPerBlock:     values[1],
+		JumpMultiplierPerBlock: values[2],
+		Kink:                   values[3],
+	}, nil
+}
+
+// GetInterestRateModels returns the rate model of each cToken keyed by model address.
+// Models are only queried the first time their address is seen since their parameters
+// can't change without deploying a new model.
+func (qe *QueryEngine) GetInterestRateModels(ctx context.Context, cTokens TokensMap) map[string]*InterestRateModel {
+	for _, cToken := range cTokens {
+		address, _ := InterfaceToAddress(cToken["interestRateModel"][0])
+		if address == "" {
+			continue
+		}
+		if _, ok := qe.rateModels[address]; ok {
+			continue
+		}
+		model, err := qe.GetInterestRateModel(ctx, address)
+		if errors.Is(err, ErrNotJumpRateModel) {
+			// not a jump rate model, it is stored as nil so it isn't queried again
+			qe.rateModels[address] = nil
+			continue
+		}
+		if err != nil {
+			// rpc errors are not cached, the model is queried again on the next tick
+			log.Error().Err(err).Str("func", "GetInterestRateModels").Msgf("failed to query interest rate model %s", address)
+			continue
+		}
+		qe.rateModels[address] = &model
+	}
+	return qe.rateModels
+}
+
+// BorrowRate returns the borrow rate per block of a jump rate model at a utilization, all values are scaled by 1e18
+func BorrowRate(model InterestRateModel, utilization *big.Int) (*big.Int, error) {
+	base, ok1 := new(big.Int).SetString(model.BaseRatePerBlock, 10)
+	multiplier, ok2 := new(big.Int).SetString(model.MultiplierPerBlock, 10)
+	jump, ok3 := new(big.Int).SetString(model.JumpMultiplierPerBlock, 10)
+	kink, ok4 := new(big.Int).SetString(model.Kink, 10)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil, errors.New("BorrowRate: invalid interest rate model")
+	}
+	mantissa := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+	// below the kink: utilization * multiplier + base
+	if utilization.Cmp(kink) <= 0 {
+		rate := new(big.Int).Mul(utilization, multiplier)
+		rate.Quo(rate, mantissa)
+		return rate.Add(rate, base), nil
+	}
+	// above the kink: the rate at the kink plus the excess utilization * jump multiplier
+	normal := new(big.Int).Mul(kink, multiplier)
+	normal.Quo(normal, mantissa).Add(normal, base)
+	excess := new(big.Int).Sub(utilization, kink)
+	rate := new(big.Int).Mul(excess, jump)
+	rate.Quo(rate, mantissa)
+	return rate.Add(rate, normal), nil
+}
+
+// SupplyRate returns the supply rate per block at a utilization: utilization * borrowRate * (1 - reserveFactor)
+func SupplyRate(borrowRate *big.Int, utilization *big.Int, reserveFactor *big.Int) *big.Int {
+	mantissa := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	rateToPool := new(big.Int).Mul(borrowRate, new(big.Int).Sub(mantissa, reserveFactor))
+	rateToPool.Quo(rateToPool, mantissa)
+	rate := new(big.Int).Mul(utilization, rateToPool)
+	return rate.Quo(rate, mantissa)
+}
+
+// GetRateCurve samples the borrow and supply APY of a market from 0 to 100% utilization in steps of step percent
+func GetRateCurve(model InterestRateModel, reserveFactor string, secondsPerBlock float64, step int64) ([]RateCurvePoint, error) {
+	if step <= 0 {
+		return nil, errors.New("GetRateCurve: step must be positive")
+	}
+	factor, ok := new(big.Int).SetString(reserveFactor, 10)
+	if !ok {
+		return nil, errors.New("GetRateCurve: invalid reserve factor")
+	}
+	curve := []RateCurvePoint{}
+	for percent := int64(0); percent <= 100; percent += step {
+		// utilization scaled by 1e18
+		utilization := new(big.Int).Mul(big.NewInt(percent), new(big.Int).Exp(big.NewInt(10), big.NewInt(16), nil))
+		borrowRate, err := BorrowRate(model, utilization)
+		if err != nil {
+			return nil, errors.New("GetRateCurve: " + err.Error())
+		}
+		supplyRate := SupplyRate(borrowRate, utilization, factor)
+		curve = append(curve, RateCurvePoint{
+			Utilization: big.NewInt(percent).String(),
+			BorrowApy:   fmt.Sprintf("%.2f", APY(borrowRate, secondsPerBlock)),
+			SupplyApy:   fmt.Sprintf("%.2f", APY(supplyRate, secondsPerBlock)),
+		})
+	}
+	return curve, nil
+}
@@ -0,0 +1,105 @@
+package queryengine
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+var testRateModel = InterestRateModel{
+	BaseRatePerBlock:       "1000000000",
+	MultiplierPerBlock:     "10000000000",
+	JumpMultiplierPerBlock: "100000000000",
+	Kink:                   "800000000000000000",
+}
+
+func TestBorrowRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		model       InterestRateModel
+		utilization string
+		want        string
+		wantErr     bool
+	}{
+		{name: "no utilization", model: testRateModel, utilization: "0", want: "1000000000"},
+		{name: "below kink", model: testRateModel, utilization: "500000000000000000", want: "6000000000"},
+		{name: "at kink", model: testRateModel, utilization: "800000000000000000", want: "9000000000"},
+		{name: "above kink", model: testRateModel, utilization: "1000000000000000000", want: "29000000000"},
+		{name: "invalid model", model: InterestRateModel{}, utilization: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilization, _ := new(big.Int).SetString(tt.utilization, 10)
+			got, err := BorrowRate(tt.model, utilization)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BorrowRate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("BorrowRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupplyRate(t *testing.T) {
+	utilization, _ := new(big.Int).SetString("500000000000000000", 10)
+	reserveFactor, _ := new(big.Int).SetString("100000000000000000", 10)
+	if got := SupplyRate(big.NewInt(6000000000), utilization, reserveFactor); got.String() != "2700000000" {
+		t.Errorf("SupplyRate() = %v, want 2700000000", got)
+	}
+}
+
+func TestGetRateCurve(t *testing.T) {
+	curve, err := GetRateCurve(testRateModel, "100000000000000000", DefaultSecondsPerBlock, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curve) != 3 {
+		t.Fatalf("GetRateCurve() returned %d points, want 3", len(curve))
+	}
+	for index, utilization := range []string{"0", "50", "100"} {
+		if curve[index].Utilization != utilization {
+			t.Errorf("GetRateCurve() point %d utilization = %v, want %v", index, curve[index].Utilization, utilization)
+		}
+	}
+	wantBorrow := fmt.Sprintf("%.2f", APY(big.NewInt(6000000000), DefaultSecondsPerBlock))
+	wantSupply := fmt.Sprintf("%.2f", APY(big.NewInt(2700000000), DefaultSecondsPerBlock))
+	if curve[1].BorrowApy != wantBorrow || curve[1].SupplyApy != wantSupply {
+		t.Errorf("GetRateCurve() at 50%% = %v, %v, want %v, %v", curve[1].BorrowApy, curve[1].SupplyApy, wantBorrow, wantSupply)
+	}
+	if curve[0].SupplyApy != "0.00" {
+		t.Errorf("GetRateCurve() supply apy at 0%% = %v, want 0.00", curve[0].SupplyApy)
+	}
+
+	if _, err := GetRateCurve(testRateModel, "invalid", DefaultSecondsPerBlock, 50); err == nil {
+		t.Errorf("GetRateCurve() expected error for invalid reserve factor")
+	}
+}
+
+// revertError mimics the json rpc error a node returns for a reverted call
+type revertError struct{}
+
+func (revertError) Error() string          { return "execution reverted" }
+func (revertError) ErrorData() interface{} { return "0x" }
+
+func TestIsExecutionReverted(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "revert with data", err: revertError{}, want: true},
+		{name: "wrapped revert", err: fmt.Errorf("call failed: %w", revertError{}), want: true},
+		{name: "revert message only", err: errors.New("execution reverted: Multicall3: call failed"), want: true},
+		{name: "rpc timeout", err: errors.New("context deadline exceeded"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isExecutionReverted(tt.err); got != tt.want {
+				t.Errorf("isExecutionReverted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
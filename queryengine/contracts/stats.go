@@ -0,0 +1,117 @@
+package queryengine
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ProtocolStats aggregates the lending markets and dex pairs, values are in usd
+type ProtocolStats struct {
+	Block string `json:"block"`
+	Time  int64  `json:"time"`
+	// value supplied to and borrowed from lending markets
+	TotalSupplied string `json:"totalSupplied"`
+	TotalBorrowed string `json:"totalBorrowed"`
+	// value of the cash held by lending markets
+	LendingLiquidity string `json:"lendingLiquidity"`
+	DexTvl           string `json:"dexTvl"`
+	// dex tvl plus lending liquidity of markets whose underlying isn't an lp token, lp tokens are already counted in dex tvl
+	TotalTvl string `json:"totalTvl"`
+	Markets  int    `json:"markets"`
+	Pairs    int    `json:"pairs"`
+	// supply apy weighted by value supplied and borrow apy weighted by value borrowed
+	AvgSupplyApy string `json:"avgSupplyApy"`
+	AvgBorrowApy string `json:"avgBorrowApy"`
+}
+
+// returns the usd value of an amount of tokens with a price scaled by 1e(36-decimals)
+func usdValue(amount *big.Int, price *big.Int) float64 {
+	return FormatUnits(new(big.Int).Mul(amount, price), 36)
+}
+
+// parses a decimal string as a big int, invalid strings are zero
+func parseBigInt(value string) *big.Int {
+	num, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return num
+}
+
+// parses a formatted float, invalid strings are zero
+func parseFloat(value string) float64 {
+	num, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return num
+}
+
+// GetProtocolStats aggregates the processed cTokens and pairs of a block
+func GetProtocolStats(blocknumber string, timestamp int64, cTokens []ProcessedCToken, pairs []ProcessedPair) ProtocolStats {
+	var supplied, borrowed, liquidity, lendingTvl, dexTvl, supplyWeighted, borrowWeighted float64
+
+	for _, cToken := range cTokens {
+		price := parseBigInt(cToken.Price)
+		cash := parseBigInt(cToken.Cash)
+		borrows := parseBigInt(cToken.TotalBorrows)
+		reserves := parseBigInt(cToken.TotalReserves)
+
+		suppliedValue := usdValue(new(big.Int).Sub(new(big.Int).Add(cash, borrows), reserves), price)
+		borrowedValue := usdValue(borrows, price)
+		cashValue := usdValue(cash, price)
+
+		supplied += suppliedValue
+		borrowed += borrowedValue
+		liquidity += cashValue
+		supplyWeighted += suppliedValue * parseFloat(cToken.SupplyApy)
+		borrowWeighted += borrowedValue * parseFloat(cToken.BorrowApy)
+		if !isPairAddress(cToken.Underlying.Address, pairs) {
+			lendingTvl += cashValue
+		}
+	}
+
+	for _, pair := range pairs {
+		// pair tvl is scaled by 1e18
+		dexTvl += parseFloat(pair.Tvl) / 1e18
+	}
+
+	var avgSupplyApy, avgBorrowApy float64
+	if supplied > 0 {
+		avgSupplyApy = supplyWeighted / supplied
+	}
+	if borrowed > 0 {
+		avgBorrowApy = borrowWeighted / borrowed
+	}
+
+	return ProtocolStats{
+		Block:            blocknumber,
+		Time:             timestamp,
+		TotalSupplied:    fmt.Sprintf("%.2f", supplied),
+		TotalBorrowed:    fmt.Sprintf("%.2f", borrowed),
+		LendingLiquidity: fmt.Sprintf("%.2f", liquidity),
+		DexTvl:           fmt.Sprintf("%.2f", dexTvl),
+		TotalTvl:         fmt.Sprintf("%.2f", dexTvl+lendingTvl),
+		Markets:          len(cTokens),
+		Pairs:            len(pairs),
+		AvgSupplyApy:     fmt.Sprintf("%.2f", avgSupplyApy),
+		AvgBorrowApy:     fmt.Sprintf("%.2f", avgBorrowApy),
+	}
+}
+
+// checks if an address is one of the pairs
+func isPairAddress(address string, pairs []ProcessedPair) bool {
+	for _, pair := range pairs {
+		if strings.EqualFold(pair.Address, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// StatsHistoryDue checks if stats taken at now fall in a later history interval than the last history entry
+func StatsHistoryDue(lastTime int64, now int64, interval int64) bool {
+	return now/interval > lastTime/interval
+}
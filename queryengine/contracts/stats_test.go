@@ -0,0 +1,93 @@
+package queryengine
+
+import (
+	"reflect"
+	"testing"
+
+	"althea-api/config"
+)
+
+func TestGetProtocolStats(t *testing.T) {
+	lpAddress := "0x1D20635535307208919f0b67c3B2065965A85aA9"
+	cTokens := []ProcessedCToken{
+		// 1000 supplied, 400 borrowed and 100 reserves of a 1 usd token with 6 decimals
+		{
+			Underlying:    config.Underlying{Address: "0x80b5a32E4F032B2a058b4F29EC95EEfEEB87aDcd"},
+			Price:         ScaledPrice(1, 6).String(),
+			Cash:          "700000000",
+			TotalBorrows:  "400000000",
+			TotalReserves: "100000000",
+			SupplyApy:     "4.00",
+			BorrowApy:     "10.00",
+		},
+		// 100 lp tokens worth 2 usd each supplied, nothing borrowed
+		{
+			Underlying:    config.Underlying{Address: "0x1d20635535307208919f0b67c3b2065965a85aa9"},
+			Price:         ScaledPrice(2, 18).String(),
+			Cash:          "100000000000000000000",
+			TotalBorrows:  "0",
+			TotalReserves: "0",
+			SupplyApy:     "1.00",
+			BorrowApy:     "0.00",
+		},
+	}
+	pairs := []ProcessedPair{
+		// tvl is scaled by 1e18
+		{Address: lpAddress, Tvl: "5000000000000000000000.00"},
+	}
+
+	want := ProtocolStats{
+		Block:            "100",
+		Time:             1700000000,
+		TotalSupplied:    "1200.00",
+		TotalBorrowed:    "400.00",
+		LendingLiquidity: "900.00",
+		DexTvl:           "5000.00",
+		TotalTvl:         "5700.00",
+		Markets:          2,
+		Pairs:            1,
+		// (1000 * 4 + 200 * 1) / 1200
+		AvgSupplyApy: "3.50",
+		AvgBorrowApy: "10.00",
+	}
+	if got := GetProtocolStats("100", 1700000000, cTokens, pairs); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetProtocolStats() = %+v, want %+v", got, want)
+	}
+
+	empty := ProtocolStats{
+		Block:            "100",
+		Time:             1700000000,
+		TotalSupplied:    "0.00",
+		TotalBorrowed:    "0.00",
+		LendingLiquidity: "0.00",
+		DexTvl:           "0.00",
+		TotalTvl:         "0.00",
+		AvgSupplyApy:     "0.00",
+		AvgBorrowApy:     "0.00",
+	}
+	if got := GetProtocolStats("100", 1700000000, nil, nil); !reflect.DeepEqual(got, empty) {
+		t.Errorf("GetProtocolStats() = %+v, want %+v", got, empty)
+	}
+}
+
+func TestStatsHistoryDue(t *testing.T) {
+	tests := []struct {
+		name     string
+		lastTime int64
+		now      int64
+		want     bool
+	}{
+		{"no history yet", 0, 1700000000, true},
+		{"same hour", 1699999200, 1700002799, false},
+		{"next hour", 1699999200, 1700002800, true},
+		{"several hours later", 1699999200, 1700020000, true},
+		{"clock behind last entry", 1700002800, 1699999200, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatsHistoryDue(tt.lastTime, tt.now, 3600); got != tt.want {
+				t.Errorf("StatsHistoryDue(%d, %d) = %v, want %v", tt.lastTime, tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -21,26 +21,70 @@ type ProcessedPair struct {
 	Reserve1    string       `json:"reserve1"`
 	Reserve2    string       `json:"reserve2"`
 	LogoURI     string       `json:"logoURI,omitempty"`
+	// trading activity in USD from indexed swaps
+	Volume24h string `json:"volume24h"`
+	Volume7d  string `json:"volume7d"`
+	Fees24h   string `json:"fees24h"`
+	Fees7d    string `json:"fees7d"`
+	FeeApr    string `json:"feeApr"`
 }
+
+// TokenPrice is the usd price of a token in human units
+type TokenPrice struct {
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Decimals int64  `json:"decimals"`
+	// usd price of the token, empty if no source has a price for it
+	Price string `json:"price"`
+	// price source of the token's cToken, "oracle" for pair oracle prices, "pair" for prices derived from pair reserves
+	// or "none" if no source has a price for the token
+	Source string `json:"source"`
+	// address of the pair the price is taken from, empty for cToken prices
+	Pair string `json:"pair,omitempty"`
+}
+
 type ProcessedCToken struct {
-	Address               string            `json:"address"`
-	Symbol                string            `json:"symbol"`
-	Name                  string            `json:"name"`
-	Decimals              int64             `json:"decimals"`
-	Underlying            config.Underlying `json:"underlying"`
-	Cash                  string            `json:"cash"`
-	ExchangeRate          string            `json:"exchangeRate"`
-	CollateralFactor      string            `json:"collateralFactor"`
-	Price                 string            `json:"price"`
-	BorrowCap             string            `json:"borrowCap"`
-	IsListed              bool              `json:"isListed"`
-	Liquidity             string            `json:"liquidity"`
-	SupplyApy             string            `json:"supplyApy"`
-	SupplyApr             string            `json:"supplyApr"`
-	BorrowApy             string            `json:"borrowApy"`
-	BorrowApr             string            `json:"borrowApr"`
-	DistApy               string            `json:"distApy"`
-	DistApr               string            `json:"distApr"`
-	CompSupplyState       string            `json:"compSupplyState"`
-	UnderlyingTotalSupply string            `json:"underlyingTotalSupply"`
+	Address          string            `json:"address"`
+	Symbol           string            `json:"symbol"`
+	Name             string            `json:"name"`
+	Decimals         int64             `json:"decimals"`
+	Underlying       config.Underlying `json:"underlying"`
+	Cash             string            `json:"cash"`
+	ExchangeRate     string            `json:"exchangeRate"`
+	CollateralFactor string            `json:"collateralFactor"`
+	// price served for the underlying, scaled by 1e(36-decimals)
+	Price string `json:"price"`
+	// price reported by the price oracle, scaled by 1e(36-decimals)
+	OraclePrice string `json:"oraclePrice"`
+	// type of the source the served price came from, oracle if the configured source had no price
+	PriceSource string `json:"priceSource"`
+	// true if the oracle price diverges from the served price by more than the price source tolerance
+	Depegged  bool   `json:"depegged"`
+	BorrowCap string `json:"borrowCap"`
+	IsListed  bool   `json:"isListed"`
+	Liquidity string `json:"liquidity"`
+	SupplyApy string `json:"supplyApy"`
+	SupplyApr string `json:"supplyApr"`
+	BorrowApy string `json:"borrowApy"`
+	BorrowApr string `json:"borrowApr"`
+	// canto rewards paid to suppliers
+	DistApy string `json:"distApy"`
+	DistApr string `json:"distApr"`
+	// canto rewards paid to borrowers
+	BorrowDistApy string `json:"borrowDistApy"`
+	BorrowDistApr string `json:"borrowDistApr"`
+	// borrow apy less borrow rewards apy
+	NetBorrowApy          string `json:"netBorrowApy"`
+	CompSupplyState       string `json:"compSupplyState"`
+	UnderlyingTotalSupply string `json:"underlyingTotalSupply"`
+	// block time in seconds used to annualize the per block rates
+	SecondsPerBlock string `json:"secondsPerBlock"`
+	// true if SecondsPerBlock was measured by the native engine, false if it is the default block time
+	BlockTimeMeasured bool `json:"blockTimeMeasured"`
+	TotalBorrows    string `json:"totalBorrows"`
+	TotalReserves   string `json:"totalReserves"`
+	ReserveFactor   string `json:"reserveFactor"`
+	Utilization     string `json:"utilization"`
+	// nil if the market doesn't use a jump rate model
+	InterestRateModel *InterestRateModel `json:"interestRateModel"`
 }
@@ -6,19 +6,36 @@ import (
 	"fmt"
 	"math"
 	"math/big"
-	"time"
+	"strings"
 
 	"althea-api/config"
 	"althea-api/multicall"
+	indexer "althea-api/queryengine/indexer"
+	"althea-api/yieldsources"
 
 	"errors"
 	"regexp"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
 )
 
-var SecondsPerBlock float64 = 5.8
-var BlocksPerDay float64 = 86400 / SecondsPerBlock
-var DaysPerYear float64 = 365
-var BlocksPerYear float64 = BlocksPerDay * DaysPerYear
+// block time used until the native engine has published a measured average block time
+const DefaultSecondsPerBlock float64 = 5.8
+const DaysPerYear float64 = 365
+
+// BlocksPerDay returns the number of blocks produced per day at the given block time
+func BlocksPerDay(secondsPerBlock float64) float64 {
+	if secondsPerBlock <= 0 {
+		secondsPerBlock = DefaultSecondsPerBlock
+	}
+	return 86400 / secondsPerBlock
+}
+
+// BlocksPerYear returns the number of blocks produced per year at the given block time
+func BlocksPerYear(secondsPerBlock float64) float64 {
+	return BlocksPerDay(secondsPerBlock) * DaysPerYear
+}
 
 func ResultToString(results interface{}) string {
 	ret, err := json.Marshal(results)
@@ -61,6 +78,14 @@ func InterfaceToString(value interface{}) (string, error) {
 	return "", errors.New("QueryEngine::InterfaceToString - Interface value is not a string")
 }
 
+// This function takes an interface value, does a type assertion to address and returns its hex string
+func InterfaceToAddress(value interface{}) (string, error) {
+	if address, ok := value.(common.Address); ok {
+		return address.Hex(), nil
+	}
+	return "", errors.New("QueryEngine::InterfaceToAddress - Interface value is not an address")
+}
+
 // This function takes  an interface value and returns a boolean
 func InterfaceToBool(value interface{}) (bool, error) {
 	//Convert interface{} type to bool
@@ -122,62 +147,48 @@ func BigIntToFloat64(value *big.Int) *big.Float {
 	return new(big.Float).SetInt(value)
 }
 
-// holiday mapping for day to days interest update
-var holidayMap = map[string]float64{
-	"30" + time.December.String(): 4,
-	"13" + time.January.String():  4,
-	"17" + time.February.String(): 4,
-	"25" + time.May.String():      4,
-}
-
-// get amount of days passed for hashnote interest update (includes holidy schedule)
-func GetInterestDaysPassed(updatedAt int64) float64 {
-	// get time from UNIX timestamp
-	unixTime := time.Unix(updatedAt, 0)
-	// check for holidays
-	daysOffset := holidayMap[fmt.Sprintf("%d%s", unixTime.Day(), unixTime.Month().String())]
-	if daysOffset != 0 {
-		return daysOffset
-	}
-	// check if saturday reporting (for three days)
-	if unixTime.Weekday() == time.Saturday || unixTime.Weekday() == time.Sunday {
-		return 3
-	}
-	return 1
-}
-
-// get hashnote apy
-func HashnoteAPY(balance *big.Int, interest *big.Int, updatedAt *big.Int) float64 {
-	prevBalance := new(big.Float).Sub(BigIntToFloat64(balance), BigIntToFloat64(interest))
-	spotAPY, _ := new(big.Float).Quo(BigIntToFloat64(interest), prevBalance).Float64()
-	dayOffset := GetInterestDaysPassed(updatedAt.Int64())
-	return (spotAPY * 365 * 100) / dayOffset
-}
-
-// APY takes the block rate, calculates APY and returns
-func APY(blockRate *big.Int) float64 {
+// APY takes the block rate and block time, calculates APY and returns
+func APY(blockRate *big.Int, secondsPerBlock float64) float64 {
 	// format blockRate by 1e18
 	formattedBlockRate := FormatUnits(blockRate, 18)
-	return (math.Pow(formattedBlockRate*BlocksPerDay+1, float64(DaysPerYear)) - 1) * 100
+	return (math.Pow(formattedBlockRate*BlocksPerDay(secondsPerBlock)+1, float64(DaysPerYear)) - 1) * 100
 }
 
-// APR takes the block rate, calculates APR and returns
-func APR(blockRate *big.Int) float64 {
+// APR takes the block rate and block time, calculates APR and returns
+func APR(blockRate *big.Int, secondsPerBlock float64) float64 {
 	// format blockRate by 1e18
 	formattedBlockRate := FormatUnits(blockRate, 18)
-	return (formattedBlockRate * BlocksPerYear) * 100
+	return (formattedBlockRate * BlocksPerYear(secondsPerBlock)) * 100
+}
+
+// Utilization returns the share of a market's supplied funds that is borrowed as a percentage:
+// borrows / (cash + borrows - reserves) * 100
+func Utilization(cash *big.Int, borrows *big.Int, reserves *big.Int) float64 {
+	supplied := new(big.Int).Sub(new(big.Int).Add(cash, borrows), reserves)
+	if supplied.Sign() <= 0 || borrows.Sign() == 0 {
+		return 0
+	}
+	utilization, _ := new(big.Float).Quo(BigIntToFloat64(borrows), BigIntToFloat64(supplied)).Float64()
+	return utilization * 100
 }
 
-// distributionAPY takes the block rate, calculates distAPY and returns
-func distributionAPY(compSupplySpeed float64, tokenSupply float64, tokenPrice float64, cantoPrice float64) float64 {
+// distributionAPR takes the reward speed per block of a market side, the amount of tokens on that side and the block time,
+// calculates the APR of the canto rewards and returns
+func distributionAPR(compSpeed float64, tokenSupply float64, tokenPrice float64, cantoPrice float64, secondsPerBlock float64) float64 {
 	if tokenSupply == 0 || tokenPrice == 0 {
 		return 0
 	}
-	return ((compSupplySpeed * (BlocksPerDay * DaysPerYear)) / tokenSupply) * (cantoPrice / tokenPrice) * 100
+	return ((compSpeed * BlocksPerYear(secondsPerBlock)) / tokenSupply) * (cantoPrice / tokenPrice) * 100
+}
+
+// CompoundAPR takes an APR in percent and returns the APY of compounding it daily
+func CompoundAPR(apr float64) float64 {
+	return (math.Pow(apr/100/DaysPerYear+1, DaysPerYear) - 1) * 100
 }
 
 // This function takes unprocessed pairs data, calculates, adds additional required data and returns the processed pair data
-func GetProcessedPairs(ctx context.Context, blocknumber string, pairs PairsMap) ([]ProcessedPair, map[string]string) {
+// volume and fees are computed from the swaps of each pair made at or after now - 7 days
+func GetProcessedPairs(ctx context.Context, blocknumber string, pairs PairsMap, swaps map[string][]indexer.IndexedEvent, now uint64) ([]ProcessedPair, map[string]string) {
 	processedPairs := []ProcessedPair{}
 	processedPairsMap := make(map[string]string)
 
@@ -205,6 +216,11 @@ func GetProcessedPairs(ctx context.Context, blocknumber string, pairs PairsMap)
 
 		// get lp pair data
 		symbol, decimals, token1, token2, stable, cDecimals, cLpAddress, logoURI := config.GetLpPairData(address)
+
+		// get trading activity, tvl is scaled by 1e18
+		formattedTvl, _ := new(big.Float).Quo(tvl, big.NewFloat(1e18)).Float64()
+		price0, price1 := PairTokenPrices(token1.Address, token2.Address, price1, price2)
+		activity := GetPairActivity(swaps[address], price0, price1, now, formattedTvl, config.GetPairSwapFee(stable))
 		processedPair := ProcessedPair{
 			Address:     address,
 			Symbol:      symbol,
@@ -224,6 +240,11 @@ func GetProcessedPairs(ctx context.Context, blocknumber string, pairs PairsMap)
 			Reserve1:    reserve1.String(),
 			Reserve2:    reserve2.String(),
 			LogoURI:     logoURI,
+			Volume24h:   fmt.Sprintf("%.2f", activity.Volume24h),
+			Volume7d:    fmt.Sprintf("%.2f", activity.Volume7d),
+			Fees24h:     fmt.Sprintf("%.2f", activity.Fees24h),
+			Fees7d:      fmt.Sprintf("%.2f", activity.Fees7d),
+			FeeApr:      fmt.Sprintf("%.2f", activity.FeeApr),
 		}
 
 		processedPairs = append(processedPairs, processedPair)
@@ -232,8 +253,71 @@ func GetProcessedPairs(ctx context.Context, blocknumber string, pairs PairsMap)
 	return processedPairs, processedPairsMap
 }
 
+// PairActivity is the trading activity of a pair in USD
+type PairActivity struct {
+	Volume24h float64
+	Volume7d  float64
+	Fees24h   float64
+	Fees7d    float64
+	FeeApr    float64
+}
+
+// PairTokenPrices orders the oracle prices of a pair's tokens as token0 and token1 of the pair contract,
+// pairs sort their tokens by address so swap amounts can be matched to prices
+func PairTokenPrices(tokenA string, tokenB string, priceA string, priceB string) (price0 string, price1 string) {
+	if strings.ToLower(tokenA) < strings.ToLower(tokenB) {
+		return priceA, priceB
+	}
+	return priceB, priceA
+}
+
+// SwapVolume returns the USD value of the input amounts of a swap,
+// prices are oracle prices scaled by 1e(36-decimals)
+func SwapVolume(swap indexer.IndexedEvent, price0 string, price1 string) float64 {
+	var volume float64
+	for _, side := range []struct{ amount, price string }{
+		{swap.Args["amount0In"], price0},
+		{swap.Args["amount1In"], price1},
+	} {
+		amount, ok := new(big.Int).SetString(side.amount, 10)
+		if !ok {
+			continue
+		}
+		price, ok := new(big.Int).SetString(side.price, 10)
+		if !ok {
+			continue
+		}
+		volume += FormatUnits(new(big.Int).Mul(amount, price), 36)
+	}
+	return volume
+}
+
+// GetPairActivity sums swap volume over the last 24 hours and 7 days before now, fees are swapFee of the volume.
+// fee APR annualizes the fees of the last 7 days over the pair's tvl in USD
+func GetPairActivity(swaps []indexer.IndexedEvent, price0 string, price1 string, now uint64, tvl float64, swapFee float64) PairActivity {
+	activity := PairActivity{}
+	for _, swap := range swaps {
+		if swap.Name != "Swap" || swap.Timestamp+7*86400 < now {
+			continue
+		}
+		volume := SwapVolume(swap, price0, price1)
+		activity.Volume7d += volume
+		if swap.Timestamp+86400 >= now {
+			activity.Volume24h += volume
+		}
+	}
+	activity.Fees24h = activity.Volume24h * swapFee
+	activity.Fees7d = activity.Volume7d * swapFee
+	if tvl > 0 {
+		activity.FeeApr = activity.Fees7d / 7 * DaysPerYear / tvl * 100
+	}
+	return activity
+}
+
 // This function takes unprocessed ctokens data, calculates, adds additional required data and returns the processed ctokens data
-func GetProcessedCTokens(ctx context.Context, cTokens TokensMap) ([]ProcessedCToken, map[string]string) {
+// rates per block are annualized using secondsPerBlock, blockTimeMeasured is false if it is the default block time
+// rateModels are keyed by model address and yieldSources replace the supply apy of cTokens with their tag, served prices are resolved from prices
+func GetProcessedCTokens(ctx context.Context, cTokens TokensMap, secondsPerBlock float64, blockTimeMeasured bool, rateModels map[string]*InterestRateModel, yieldSources map[string]yieldsources.YieldSource, prices PriceInputs) ([]ProcessedCToken, map[string]string) {
 	processedCTokens := []ProcessedCToken{}
 	processedCTokensMap := make(map[string]string)
 
@@ -272,49 +356,63 @@ func GetProcessedCTokens(ctx context.Context, cTokens TokensMap) ([]ProcessedCTo
 
 		// get supplyApy using APY()
 		supplyBlockRate, _ := InterfaceToBigInt(cToken["supplyRatePerBlock"][0])
-		supplyApy := APY(supplyBlockRate)
-		supplyApr := APR(supplyBlockRate)
+		supplyApy := APY(supplyBlockRate, secondsPerBlock)
+		supplyApr := APR(supplyBlockRate, secondsPerBlock)
 
 		// check tags that may affect this supply rate number
 		for _, tag := range tags {
-			if tag == "hashnote" {
-				// use latest round details to calculate supplyApy
-				balance, _ := InterfaceToBigInt(cToken["latestRoundDetails"][1])
-				interest, _ := InterfaceToBigInt(cToken["latestRoundDetails"][2])
-				updatedAt, _ := InterfaceToBigInt(cToken["latestRoundDetails"][4])
-				supplyApy = HashnoteAPY(balance, interest, updatedAt) - 0.5
-				supplyApr = HashnoteAPY(balance, interest, updatedAt) - 0.5
+			source, ok := yieldSources[tag]
+			if !ok {
+				continue
 			}
-			if tag == "fbill" {
-				supplyApy = 4.90
-				supplyApr = 4.90
+			// keep the market supply rate if the source can't compute its apy
+			if apy, err := source.SupplyAPY(cToken); err == nil {
+				supplyApy = apy
+				supplyApr = apy
 			}
 		}
 
 		// get borrowApy using APY()
 		borrowBlockRate, _ := InterfaceToBigInt(cToken["borrowRatePerBlock"][0])
-		borrowApy := APY(borrowBlockRate)
-		borrowApr := APR(borrowBlockRate)
+		borrowApy := APY(borrowBlockRate, secondsPerBlock)
+		borrowApr := APR(borrowBlockRate, secondsPerBlock)
 		compSupplySpeed, _ := InterfaceToBigInt(cToken["compSupplySpeeds"][0])
-		// format compSupplySpeed by 1e18
+		compBorrowSpeed, _ := InterfaceToBigInt(cToken["compBorrowSpeeds"][0])
+		// format comp speeds by 1e18
 		formattedCompSupplySpeed := FormatUnits(compSupplySpeed, 18)
+		formattedCompBorrowSpeed := FormatUnits(compBorrowSpeed, 18)
 
-		// format cash by 1e(decimals)
-		formattedTokenSupply := FormatUnits(cash, underlying.Decimals)
+		// get borrows and reserves
+		totalBorrows, _ := InterfaceToBigInt(cToken["totalBorrows"][0])
+		totalReserves, _ := InterfaceToBigInt(cToken["totalReserves"][0])
+
+		// format supplied (cash + borrows - reserves) and borrowed amounts by 1e(decimals)
+		supplied := new(big.Int).Sub(new(big.Int).Add(cash, totalBorrows), totalReserves)
+		formattedTokenSupply := FormatUnits(supplied, underlying.Decimals)
+		formattedTokenBorrows := FormatUnits(totalBorrows, underlying.Decimals)
 
 		// format token price by 1e(36-decimals)
 		formattedTokenPrice := FormatUnits(price, int64(36)-underlying.Decimals)
 
-		distApy := distributionAPY(formattedCompSupplySpeed, formattedTokenSupply, formattedTokenPrice, formattedCantoPrice)
-		distApr := distributionAPY(formattedCompSupplySpeed, formattedTokenSupply, formattedTokenPrice, formattedCantoPrice)
-		// Set price of cNOTE, cUSDC, cUSDT to exactly 1USD scaled by 1e(36-decimals)
-		if symbol == "cNOTE" || symbol == "cUSDC" || symbol == "cUSDT" {
-			price.Exp(big.NewInt(10), big.NewInt(36-underlying.Decimals), nil)
+		// supply rewards are shared by all suppliers and borrow rewards by all borrowers
+		distApr := distributionAPR(formattedCompSupplySpeed, formattedTokenSupply, formattedTokenPrice, formattedCantoPrice, secondsPerBlock)
+		distApy := CompoundAPR(distApr)
+		borrowDistApr := distributionAPR(formattedCompBorrowSpeed, formattedTokenBorrows, formattedTokenPrice, formattedCantoPrice, secondsPerBlock)
+		borrowDistApy := CompoundAPR(borrowDistApr)
+		// serve the price from the configured price source, the oracle price is served if the source has no price
+		priceSource := config.GetCTokenPriceSource(address)
+		servedPrice, priceSourceType, depegged, err := ResolvePrice(priceSource, underlying.Address, underlying.Decimals, price, prices)
+		if err != nil {
+			log.Warn().Err(err).Str("func", "GetProcessedCTokens").Msgf("serving oracle price for cToken %s", address)
 		}
 
 		// get underlying total supply
 		underlyingTotalSupply, _ := InterfaceToString(cToken["underlyingSupply"][0])
 
+		// get reserve factor and the interest rate model
+		reserveFactor, _ := InterfaceToBigInt(cToken["reserveFactorMantissa"][0])
+		rateModelAddress, _ := InterfaceToAddress(cToken["interestRateModel"][0])
+
 		processedCToken := ProcessedCToken{
 			Address:               address,
 			Symbol:                symbol,
@@ -325,7 +423,10 @@ func GetProcessedCTokens(ctx context.Context, cTokens TokensMap) ([]ProcessedCTo
 			ExchangeRate:          exchangeRate,
 			IsListed:              isListed,
 			CollateralFactor:      collateralFactor,
-			Price:                 price.String(),
+			Price:                 servedPrice.String(),
+			OraclePrice:           price.String(),
+			PriceSource:           priceSourceType,
+			Depegged:              depegged,
 			BorrowCap:             borrowCap.String(),
 			Liquidity:             fmt.Sprintf("%.2f", liquidity),
 			SupplyApy:             fmt.Sprintf("%.2f", supplyApy),
@@ -334,8 +435,18 @@ func GetProcessedCTokens(ctx context.Context, cTokens TokensMap) ([]ProcessedCTo
 			BorrowApr:             fmt.Sprintf("%.2f", borrowApr),
 			DistApy:               fmt.Sprintf("%.2f", distApy),
 			DistApr:               fmt.Sprintf("%.2f", distApr),
+			BorrowDistApy:         fmt.Sprintf("%.2f", borrowDistApy),
+			BorrowDistApr:         fmt.Sprintf("%.2f", borrowDistApr),
+			NetBorrowApy:          fmt.Sprintf("%.2f", borrowApy-borrowDistApy),
 			CompSupplyState:       compSupplyState,
 			UnderlyingTotalSupply: underlyingTotalSupply,
+			SecondsPerBlock:       fmt.Sprintf("%.2f", secondsPerBlock),
+			BlockTimeMeasured:     blockTimeMeasured,
+			TotalBorrows:          totalBorrows.String(),
+			TotalReserves:         totalReserves.String(),
+			ReserveFactor:         reserveFactor.String(),
+			Utilization:           fmt.Sprintf("%.2f", Utilization(cash, totalBorrows, totalReserves)),
+			InterestRateModel:     rateModels[rateModelAddress],
 		}
 
 		processedCTokens = append(processedCTokens, processedCToken)
@@ -2,8 +2,10 @@ package queryengine
 
 import (
 	"althea-api/multicall"
+	indexer "althea-api/queryengine/indexer"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"testing"
@@ -557,14 +559,14 @@ func TestAPY(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := APY(tt.args.blockRate); got != tt.want {
+			if got := APY(tt.args.blockRate, DefaultSecondsPerBlock); got != tt.want {
 				t.Errorf("APY() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_distributionAPY(t *testing.T) {
+func Test_distributionAPR(t *testing.T) {
 	type args struct {
 		compSupplySpeed float64
 		tokenSupply     float64
@@ -609,8 +611,8 @@ func Test_distributionAPY(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := distributionAPY(tt.args.compSupplySpeed, tt.args.tokenSupply, tt.args.tokenPrice, tt.args.cantoPrice); got != tt.want {
-				t.Errorf("distributionAPY() = %v, want %v", got, tt.want)
+			if got := distributionAPR(tt.args.compSupplySpeed, tt.args.tokenSupply, tt.args.tokenPrice, tt.args.cantoPrice, DefaultSecondsPerBlock); got != tt.want {
+				t.Errorf("distributionAPR() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -988,3 +990,156 @@ func Test_distributionAPY(t *testing.T) {
 // 		})
 // 	}
 // }
+
+func TestUtilization(t *testing.T) {
+	type args struct {
+		cash     *big.Int
+		borrows  *big.Int
+		reserves *big.Int
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "no borrows",
+			args: args{
+				cash:     big.NewInt(1000),
+				borrows:  big.NewInt(0),
+				reserves: big.NewInt(0),
+			},
+			want: 0,
+		},
+		{
+			name: "half borrowed",
+			args: args{
+				cash:     big.NewInt(500),
+				borrows:  big.NewInt(500),
+				reserves: big.NewInt(0),
+			},
+			want: 50,
+		},
+		{
+			name: "reserves reduce supplied",
+			args: args{
+				cash:     big.NewInt(600),
+				borrows:  big.NewInt(800),
+				reserves: big.NewInt(400),
+			},
+			want: 80,
+		},
+		{
+			name: "nothing supplied",
+			args: args{
+				cash:     big.NewInt(0),
+				borrows:  big.NewInt(100),
+				reserves: big.NewInt(100),
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Utilization(tt.args.cash, tt.args.borrows, tt.args.reserves); got != tt.want {
+				t.Errorf("Utilization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPairTokenPrices(t *testing.T) {
+	note := "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503"
+	wcanto := "0x826551890Dc65655a0Aceca109aB11AbDbD7a07B"
+	price0, price1 := PairTokenPrices(wcanto, note, "2", "1")
+	if price0 != "1" || price1 != "2" {
+		t.Errorf("PairTokenPrices() = %v, %v, want 1, 2", price0, price1)
+	}
+	price0, price1 = PairTokenPrices(note, wcanto, "1", "2")
+	if price0 != "1" || price1 != "2" {
+		t.Errorf("PairTokenPrices() = %v, %v, want 1, 2", price0, price1)
+	}
+}
+
+func TestGetPairActivity(t *testing.T) {
+	// 1 USD for an 18 decimal token and 0.5 USD for a 6 decimal token, scaled by 1e(36-decimals)
+	price0 := "1000000000000000000"
+	price1 := "500000000000000000000000000000"
+	now := uint64(1700000000)
+	swap := func(name string, timestamp uint64, amount0In string, amount1In string) indexer.IndexedEvent {
+		return indexer.IndexedEvent{
+			Name:      name,
+			Timestamp: timestamp,
+			Args:      map[string]string{"amount0In": amount0In, "amount1In": amount1In, "amount0Out": "0", "amount1Out": "0"},
+		}
+	}
+	tests := []struct {
+		name  string
+		swaps []indexer.IndexedEvent
+		tvl   float64
+		want  PairActivity
+	}{
+		{
+			name: "no swaps",
+			tvl:  1000,
+			want: PairActivity{},
+		},
+		{
+			name: "swaps in and out of windows",
+			swaps: []indexer.IndexedEvent{
+				// 100 USD of token0 an hour ago
+				swap("Swap", now-3600, "100000000000000000000", "0"),
+				// 100 USD of token1 two days ago
+				swap("Swap", now-2*86400, "0", "200000000"),
+				// older than 7 days
+				swap("Swap", now-8*86400, "100000000000000000000", "0"),
+				// not a swap
+				swap("Sync", now, "100000000000000000000", "0"),
+			},
+			tvl: 1000,
+			want: PairActivity{
+				Volume24h: 100,
+				Volume7d:  200,
+				Fees24h:   0.01,
+				Fees7d:    0.02,
+				FeeApr:    0.02 / 7 * 365 / 1000 * 100,
+			},
+		},
+		{
+			name:  "no tvl",
+			swaps: []indexer.IndexedEvent{swap("Swap", now, "100000000000000000000", "0")},
+			want: PairActivity{
+				Volume24h: 100,
+				Volume7d:  100,
+				Fees24h:   0.01,
+				Fees7d:    0.01,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetPairActivity(tt.swaps, price0, price1, now, tt.tvl, 0.0001); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPairActivity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompoundAPR(t *testing.T) {
+	tests := []struct {
+		name string
+		apr  float64
+		want float64
+	}{
+		{name: "zero apr", apr: 0, want: 0},
+		{name: "10% apr", apr: 10, want: 10.52},
+		{name: "100% apr", apr: 100, want: 171.46},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := math.Round(CompoundAPR(tt.apr)*100) / 100; got != tt.want {
+				t.Errorf("CompoundAPR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
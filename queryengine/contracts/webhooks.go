@@ -0,0 +1,108 @@
+package queryengine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"althea-api/config"
+)
+
+// UtilizationCrossed checks if utilization rose from below the threshold to at or above it
+func UtilizationCrossed(previous float64, current float64, threshold float64) bool {
+	return previous < threshold && current >= threshold
+}
+
+// TvlDropped checks if tvl dropped by at least threshold percent
+func TvlDropped(previous float64, current float64, threshold float64) bool {
+	if previous <= 0 {
+		return false
+	}
+	return (previous-current)/previous*100 >= threshold
+}
+
+// ruleMatchesAddress checks if a rule applies to the address, rules without an address apply to every address
+func ruleMatchesAddress(rule config.WebhookRule, address string) bool {
+	return rule.Address == "" || strings.EqualFold(rule.Address, address)
+}
+
+// notify webhooks of cTokens whose utilization crossed the rule threshold since the previous tick
+func (qe *QueryEngine) notifyCTokenUtilization(ctx context.Context, blocknumber string, cTokens TokensMap) {
+	current := make(map[string]float64)
+	for address, cToken := range cTokens {
+		cash, _ := InterfaceToBigInt(cToken["cash"][0])
+		borrows, _ := InterfaceToBigInt(cToken["totalBorrows"][0])
+		reserves, _ := InterfaceToBigInt(cToken["totalReserves"][0])
+		current[address] = Utilization(cash, borrows, reserves)
+	}
+	for _, match := range qe.webhooks.Rules(config.RuleCTokenUtilization) {
+		for address, utilization := range current {
+			previous, ok := qe.utilization[address]
+			if !ok || !ruleMatchesAddress(match.Rule, address) || !UtilizationCrossed(previous, utilization, match.Rule.Threshold) {
+				continue
+			}
+			qe.webhooks.Notify(ctx, match, fmt.Sprintf("ctoken-utilization:%s:%s", address, blocknumber), map[string]interface{}{
+				"address":     address,
+				"block":       blocknumber,
+				"previous":    previous,
+				"utilization": utilization,
+			})
+		}
+	}
+	qe.utilization = current
+}
+
+// NextTvlBaseline returns the baseline of a pair after a tick and if its tvl dropped from the baseline by the threshold.
+// The baseline follows tvl up, so drops are measured from the highest tvl since the last notification,
+// and is reset to tvl once a drop is notified.
+func NextTvlBaseline(baseline float64, tvl float64, threshold float64) (float64, bool) {
+	if TvlDropped(baseline, tvl, threshold) {
+		return tvl, true
+	}
+	if tvl > baseline {
+		return tvl, false
+	}
+	return baseline, false
+}
+
+// notify webhooks of pairs whose TVL dropped by the rule threshold from its baseline,
+// so drops spread over many ticks are notified as well
+func (qe *QueryEngine) notifyPairTvlDrop(ctx context.Context, blocknumber string, pairs []ProcessedPair) {
+	current := make(map[string]float64)
+	for _, pair := range pairs {
+		tvl, err := strconv.ParseFloat(pair.Tvl, 64)
+		if err != nil {
+			continue
+		}
+		current[pair.Address] = tvl
+	}
+	for _, match := range qe.webhooks.Rules(config.RulePairTvlDrop) {
+		baselines, ok := qe.pairTvlBaselines[match.Webhook.Name]
+		if !ok {
+			baselines = make(map[string]float64)
+			qe.pairTvlBaselines[match.Webhook.Name] = baselines
+		}
+		for address, tvl := range current {
+			if !ruleMatchesAddress(match.Rule, address) {
+				continue
+			}
+			baseline, ok := baselines[address]
+			if !ok {
+				baselines[address] = tvl
+				continue
+			}
+			next, dropped := NextTvlBaseline(baseline, tvl, match.Rule.Threshold)
+			baselines[address] = next
+			if !dropped {
+				continue
+			}
+			qe.webhooks.Notify(ctx, match, fmt.Sprintf("pair-tvl-drop:%s:%s", address, blocknumber), map[string]interface{}{
+				"address":  address,
+				"block":    blocknumber,
+				"previous": baseline,
+				"tvl":      tvl,
+			})
+		}
+	}
+}
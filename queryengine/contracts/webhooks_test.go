@@ -0,0 +1,89 @@
+package queryengine
+
+import "testing"
+
+func TestUtilizationCrossed(t *testing.T) {
+	tests := []struct {
+		name      string
+		previous  float64
+		current   float64
+		threshold float64
+		want      bool
+	}{
+		{name: "crossed", previous: 79, current: 81, threshold: 80, want: true},
+		{name: "reached exactly", previous: 79, current: 80, threshold: 80, want: true},
+		{name: "already above", previous: 85, current: 90, threshold: 80, want: false},
+		{name: "still below", previous: 70, current: 75, threshold: 80, want: false},
+		{name: "fell below", previous: 85, current: 75, threshold: 80, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UtilizationCrossed(tt.previous, tt.current, tt.threshold); got != tt.want {
+				t.Errorf("UtilizationCrossed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTvlDropped(t *testing.T) {
+	tests := []struct {
+		name      string
+		previous  float64
+		current   float64
+		threshold float64
+		want      bool
+	}{
+		{name: "dropped past threshold", previous: 1000, current: 700, threshold: 20, want: true},
+		{name: "dropped exactly threshold", previous: 1000, current: 800, threshold: 20, want: true},
+		{name: "small drop", previous: 1000, current: 900, threshold: 20, want: false},
+		{name: "increased", previous: 1000, current: 1500, threshold: 20, want: false},
+		{name: "no previous tvl", previous: 0, current: 0, threshold: 20, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TvlDropped(tt.previous, tt.current, tt.threshold); got != tt.want {
+				t.Errorf("TvlDropped() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextTvlBaseline(t *testing.T) {
+	tests := []struct {
+		name      string
+		ticks     []float64
+		threshold float64
+		want      []bool
+	}{
+		{
+			name:      "gradual drop across ticks",
+			ticks:     []float64{1000, 950, 900, 850, 790, 780},
+			threshold: 20,
+			want:      []bool{false, false, false, false, true, false},
+		},
+		{
+			name:      "drop measured from the highest tvl",
+			ticks:     []float64{1000, 1200, 1100, 950},
+			threshold: 20,
+			want:      []bool{false, false, false, true},
+		},
+		{
+			name:      "baseline resets after a notification",
+			ticks:     []float64{1000, 700, 600, 550},
+			threshold: 20,
+			want:      []bool{false, true, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := tt.ticks[0]
+			for index, tvl := range tt.ticks {
+				var dropped bool
+				baseline, dropped = NextTvlBaseline(baseline, tvl, tt.threshold)
+				if dropped != tt.want[index] {
+					t.Errorf("NextTvlBaseline() at tick %d with tvl %v = %v, want %v", index, tvl, dropped, tt.want[index])
+				}
+			}
+		})
+	}
+}
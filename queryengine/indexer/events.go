@@ -0,0 +1,106 @@
+package queryengine
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// events emitted by cTokens, the comptroller and dex pairs
+const EventsABI = `[
+	{"anonymous":false,"inputs":[{"indexed":false,"name":"minter","type":"address"},{"indexed":false,"name":"mintAmount","type":"uint256"},{"indexed":false,"name":"mintTokens","type":"uint256"}],"name":"Mint","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":false,"name":"redeemer","type":"address"},{"indexed":false,"name":"redeemAmount","type":"uint256"},{"indexed":false,"name":"redeemTokens","type":"uint256"}],"name":"Redeem","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":false,"name":"borrower","type":"address"},{"indexed":false,"name":"borrowAmount","type":"uint256"},{"indexed":false,"name":"accountBorrows","type":"uint256"},{"indexed":false,"name":"totalBorrows","type":"uint256"}],"name":"Borrow","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":false,"name":"payer","type":"address"},{"indexed":false,"name":"borrower","type":"address"},{"indexed":false,"name":"repayAmount","type":"uint256"},{"indexed":false,"name":"accountBorrows","type":"uint256"},{"indexed":false,"name":"totalBorrows","type":"uint256"}],"name":"RepayBorrow","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":false,"name":"liquidator","type":"address"},{"indexed":false,"name":"borrower","type":"address"},{"indexed":false,"name":"repayAmount","type":"uint256"},{"indexed":false,"name":"cTokenCollateral","type":"address"},{"indexed":false,"name":"seizeTokens","type":"uint256"}],"name":"LiquidateBorrow","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":false,"name":"cToken","type":"address"},{"indexed":false,"name":"account","type":"address"}],"name":"MarketEntered","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":false,"name":"cToken","type":"address"},{"indexed":false,"name":"account","type":"address"}],"name":"MarketExited","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":false,"name":"amount0In","type":"uint256"},{"indexed":false,"name":"amount1In","type":"uint256"},{"indexed":false,"name":"amount0Out","type":"uint256"},{"indexed":false,"name":"amount1Out","type":"uint256"},{"indexed":true,"name":"to","type":"address"}],"name":"Swap","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":false,"name":"reserve0","type":"uint256"},{"indexed":false,"name":"reserve1","type":"uint256"}],"name":"Sync","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Transfer","type":"event"}
+]`
+
+var eventsABI = newEventsABI()
+
+func newEventsABI() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(EventsABI))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// EventTopics returns the topic ids of all indexed events, used to filter logs
+func EventTopics() []common.Hash {
+	topics := make([]common.Hash, 0, len(eventsABI.Events))
+	for _, event := range eventsABI.Events {
+		topics = append(topics, event.ID)
+	}
+	return topics
+}
+
+// IndexedEvent is a decoded log with its block and transaction metadata
+type IndexedEvent struct {
+	Name        string            `json:"name"`
+	Address     string            `json:"address"`
+	BlockNumber uint64            `json:"blockNumber"`
+	BlockHash   string            `json:"blockHash"`
+	Timestamp   uint64            `json:"timestamp"`
+	TxHash      string            `json:"txHash"`
+	TxIndex     uint              `json:"txIndex"`
+	LogIndex    uint              `json:"logIndex"`
+	Args        map[string]string `json:"args"`
+}
+
+// DecodeLog decodes a log emitted by one of the indexed events, amounts and addresses are returned as strings
+func DecodeLog(vLog types.Log) (IndexedEvent, error) {
+	if len(vLog.Topics) == 0 {
+		return IndexedEvent{}, errors.New("DecodeLog: log has no topics")
+	}
+	event, err := eventsABI.EventByID(vLog.Topics[0])
+	if err != nil {
+		return IndexedEvent{}, errors.New("DecodeLog: " + err.Error())
+	}
+
+	values := make(map[string]interface{})
+	if err := eventsABI.UnpackIntoMap(values, event.Name, vLog.Data); err != nil {
+		return IndexedEvent{}, errors.New("DecodeLog: " + err.Error())
+	}
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, vLog.Topics[1:]); err != nil {
+		return IndexedEvent{}, errors.New("DecodeLog: " + err.Error())
+	}
+
+	args := make(map[string]string, len(values))
+	for name, value := range values {
+		switch v := value.(type) {
+		case *big.Int:
+			args[name] = v.String()
+		case common.Address:
+			args[name] = v.Hex()
+		default:
+			args[name] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	return IndexedEvent{
+		Name:        event.Name,
+		Address:     vLog.Address.Hex(),
+		BlockNumber: vLog.BlockNumber,
+		BlockHash:   vLog.BlockHash.Hex(),
+		TxHash:      vLog.TxHash.Hex(),
+		TxIndex:     vLog.TxIndex,
+		LogIndex:    vLog.Index,
+		Args:        args,
+	}, nil
+}
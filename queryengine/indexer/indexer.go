@@ -0,0 +1,243 @@
+package queryengine
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"althea-api/config"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// number of blocks requested per FilterLogs call
+	BatchSize uint64 = 1000
+	// number of blocks rolled back when the last indexed block is no longer canonical
+	ReorgDepth uint64 = 50
+	// events older than the retention are pruned, pair activity reads the last 7 days of swaps
+	Retention = 14 * 24 * time.Hour
+	// minimum time between prunes of old events
+	PruneInterval = time.Hour
+	// number of blocks the block time is averaged over to find the retention height
+	RetentionSampleBlocks uint64 = 1000
+)
+
+// Indexer fetches logs emitted by the lending market and dex contracts
+// and stores the decoded events in a Redis database on a regular interval.
+type Indexer struct {
+	redisclient *redis.Client
+	interval    time.Duration
+	addresses   []common.Address
+	topics      []common.Hash
+	startHeight uint64
+	lastPrune   time.Time
+}
+
+// Returns an Indexer instance for the contracts in the token list
+func NewIndexer() *Indexer {
+	return &Indexer{
+		redisclient: config.RDB,
+		interval:    time.Duration(config.QueryInterval),
+		addresses:   IndexedAddresses(config.FPIConfig),
+		topics:      EventTopics(),
+		startHeight: config.IndexerStartHeight,
+	}
+}
+
+// IndexedAddresses returns the addresses of the cTokens, comptroller and pairs in the token list
+func IndexedAddresses(fpi config.TokensInfo) []common.Address {
+	addresses := []common.Address{}
+	for _, cToken := range fpi.CTokens {
+		addresses = append(addresses, common.HexToAddress(cToken.Address))
+	}
+	if fpi.Comptroller != "" {
+		addresses = append(addresses, common.HexToAddress(fpi.Comptroller))
+	}
+	for _, pair := range fpi.Pairs {
+		addresses = append(addresses, common.HexToAddress(pair.Address))
+	}
+	return addresses
+}
+
+// NextRange returns the block range to index starting at next, ok is false if next is past the latest block
+func NextRange(next uint64, latest uint64, batchSize uint64) (from uint64, to uint64, ok bool) {
+	if next > latest {
+		return 0, 0, false
+	}
+	to = next + batchSize - 1
+	if to > latest {
+		to = latest
+	}
+	return next, to, true
+}
+
+// RollbackHeight returns the block to continue indexing from after a reorg is detected at next,
+// it never goes below the start height
+func RollbackHeight(next uint64, depth uint64, start uint64) uint64 {
+	if next < start+depth {
+		return start
+	}
+	return next - depth
+}
+
+// RetentionHeight returns the lowest block kept when pruning events older than retention,
+// the block time is averaged between a past block and the latest block
+func RetentionHeight(pastHeight uint64, pastTime uint64, latestHeight uint64, latestTime uint64, retention time.Duration) uint64 {
+	if latestHeight <= pastHeight || latestTime <= pastTime {
+		return 0
+	}
+	secondsPerBlock := float64(latestTime-pastTime) / float64(latestHeight-pastHeight)
+	retentionBlocks := uint64(retention.Seconds() / secondsPerBlock)
+	if latestHeight < retentionBlocks {
+		return 0
+	}
+	return latestHeight - retentionBlocks
+}
+
+// prunes events older than the retention, at most once per prune interval
+func (idx *Indexer) pruneEvents(ctx context.Context, latest uint64) error {
+	if time.Since(idx.lastPrune) < PruneInterval || latest < RetentionSampleBlocks {
+		return nil
+	}
+	latestHeader, err := config.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(latest))
+	if err != nil {
+		return errors.New("pruneEvents: " + err.Error())
+	}
+	pastHeader, err := config.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(latest-RetentionSampleBlocks))
+	if err != nil {
+		return errors.New("pruneEvents: " + err.Error())
+	}
+	retentionHeight := RetentionHeight(latest-RetentionSampleBlocks, pastHeader.Time, latest, latestHeader.Time, Retention)
+	if retentionHeight > 0 {
+		if err := idx.prune(ctx, retentionHeight); err != nil {
+			return errors.New("pruneEvents: " + err.Error())
+		}
+	}
+	idx.lastPrune = time.Now()
+	return nil
+}
+
+// checks if the last indexed block is still canonical and rolls back recent blocks if it isn't
+func (idx *Indexer) handleReorg(ctx context.Context, next uint64) (uint64, error) {
+	lastHash, err := idx.getLastBlockHash(ctx)
+	if err != nil || lastHash == (common.Hash{}) || next == 0 {
+		return next, err
+	}
+	header, err := config.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(next-1))
+	if err != nil {
+		return next, errors.New("handleReorg: " + err.Error())
+	}
+	if header.Hash() == lastHash {
+		return next, nil
+	}
+
+	rollback := RollbackHeight(next, ReorgDepth, idx.startHeight)
+	log.Warn().Uint64("height", next-1).Uint64("rollback", rollback).Msg("reorg detected, rolling back indexed events")
+	if err := idx.rollback(ctx, rollback); err != nil {
+		return next, errors.New("handleReorg: " + err.Error())
+	}
+	return rollback, nil
+}
+
+// indexes logs between from and to (inclusive) and stores them
+func (idx *Indexer) indexRange(ctx context.Context, from uint64, to uint64) error {
+	logs, err := config.EthClient.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: idx.addresses,
+		Topics:    [][]common.Hash{idx.topics},
+	})
+	if err != nil {
+		return errors.New("indexRange: " + err.Error())
+	}
+
+	// block timestamps are only fetched for blocks with logs
+	timestamps := make(map[uint64]uint64)
+	events := make([]IndexedEvent, 0, len(logs))
+	for _, vLog := range logs {
+		if vLog.Removed {
+			continue
+		}
+		event, err := DecodeLog(vLog)
+		if err != nil {
+			log.Error().Err(err).Str("func", "indexRange").Str("tx", vLog.TxHash.Hex()).Msg("failed to decode log")
+			continue
+		}
+		timestamp, ok := timestamps[vLog.BlockNumber]
+		if !ok {
+			header, err := config.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(vLog.BlockNumber))
+			if err != nil {
+				return errors.New("indexRange: " + err.Error())
+			}
+			timestamp = header.Time
+			timestamps[vLog.BlockNumber] = timestamp
+		}
+		event.Timestamp = timestamp
+		events = append(events, event)
+	}
+
+	// the hash of the last block in the range is stored to detect reorgs on the next sync
+	header, err := config.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return errors.New("indexRange: " + err.Error())
+	}
+	return idx.storeEvents(ctx, events, to+1, header.Hash())
+}
+
+// Sync indexes all blocks from the stored height up to the latest block, backfilling from the start height
+// the first time it runs
+func (idx *Indexer) Sync(ctx context.Context) error {
+	latest, err := config.EthClient.BlockNumber(ctx)
+	if err != nil {
+		return errors.New("Sync: " + err.Error())
+	}
+	next, ok, err := idx.getNextBlock(ctx)
+	if err != nil {
+		return errors.New("Sync: " + err.Error())
+	}
+	if !ok {
+		next = idx.startHeight
+	}
+	next, err = idx.handleReorg(ctx, next)
+	if err != nil {
+		return errors.New("Sync: " + err.Error())
+	}
+
+	for {
+		from, to, ok := NextRange(next, latest, BatchSize)
+		if !ok {
+			return idx.pruneEvents(ctx, latest)
+		}
+		if err := idx.indexRange(ctx, from, to); err != nil {
+			return errors.New("Sync: " + err.Error())
+		}
+		log.Info().Uint64("from", from).Uint64("to", to).Msg("indexed events")
+		next = to + 1
+	}
+}
+
+// StartIndexer syncs the indexer on the interval specified in config
+func (idx *Indexer) StartIndexer(ctx context.Context) {
+	log.Info().Uint64("start", idx.startHeight).Msg("starting event indexer")
+	ticker := time.NewTicker(idx.interval * time.Second)
+	for range ticker.C {
+		if err := idx.Sync(ctx); err != nil {
+			log.Error().Err(err).Str("func", "StartIndexer").Msg("failed to sync event indexer")
+		}
+	}
+}
+
+// Run initializes an Indexer instance and starts it if a start height is configured.
+func Run(ctx context.Context) {
+	if !config.IndexerEnabled {
+		log.Info().Msg("event indexer disabled, set INDEXER_START_HEIGHT to enable it")
+		return
+	}
+	idx := NewIndexer()
+	idx.StartIndexer(ctx)
+}
@@ -0,0 +1,194 @@
+package queryengine
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"althea-api/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDecodeLog(t *testing.T) {
+	pair := common.HexToAddress("0x1D20635535307208919f0b67c3B2065965A85aA9")
+	cToken := common.HexToAddress("0xEe602429Ef7eCe0a13e4FfE8dBC16e101049504C")
+	sender := common.HexToAddress("0xa252eEE9BDe830Ca4793F054B506587027825a8e")
+	account := common.HexToAddress("0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503")
+
+	swap := eventsABI.Events["Swap"]
+	swapData, err := swap.Inputs.NonIndexed().Pack(big.NewInt(100), big.NewInt(0), big.NewInt(0), big.NewInt(95))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mint := eventsABI.Events["Mint"]
+	mintData, err := mint.Inputs.NonIndexed().Pack(account, big.NewInt(1000), big.NewInt(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		log     types.Log
+		want    IndexedEvent
+		wantErr bool
+	}{
+		{
+			name: "swap with indexed topics",
+			log: types.Log{
+				Address:     pair,
+				Topics:      []common.Hash{swap.ID, common.BytesToHash(sender.Bytes()), common.BytesToHash(account.Bytes())},
+				Data:        swapData,
+				BlockNumber: 10,
+				TxIndex:     1,
+				Index:       3,
+			},
+			want: IndexedEvent{
+				Name:        "Swap",
+				Address:     pair.Hex(),
+				BlockNumber: 10,
+				BlockHash:   common.Hash{}.Hex(),
+				TxHash:      common.Hash{}.Hex(),
+				TxIndex:     1,
+				LogIndex:    3,
+				Args: map[string]string{
+					"sender":     sender.Hex(),
+					"to":         account.Hex(),
+					"amount0In":  "100",
+					"amount1In":  "0",
+					"amount0Out": "0",
+					"amount1Out": "95",
+				},
+			},
+		},
+		{
+			name: "mint",
+			log: types.Log{
+				Address:     cToken,
+				Topics:      []common.Hash{mint.ID},
+				Data:        mintData,
+				BlockNumber: 12,
+			},
+			want: IndexedEvent{
+				Name:        "Mint",
+				Address:     cToken.Hex(),
+				BlockNumber: 12,
+				BlockHash:   common.Hash{}.Hex(),
+				TxHash:      common.Hash{}.Hex(),
+				Args: map[string]string{
+					"minter":     account.Hex(),
+					"mintAmount": "1000",
+					"mintTokens": "50",
+				},
+			},
+		},
+		{
+			name:    "no topics",
+			log:     types.Log{Address: pair},
+			wantErr: true,
+		},
+		{
+			name:    "unknown event",
+			log:     types.Log{Address: pair, Topics: []common.Hash{common.HexToHash("0x01")}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeLog(tt.log)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeLog() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeLog() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		next     uint64
+		latest   uint64
+		wantFrom uint64
+		wantTo   uint64
+		wantOk   bool
+	}{
+		{name: "full batch", next: 100, latest: 5000, wantFrom: 100, wantTo: 1099, wantOk: true},
+		{name: "partial batch", next: 100, latest: 150, wantFrom: 100, wantTo: 150, wantOk: true},
+		{name: "single block", next: 150, latest: 150, wantFrom: 150, wantTo: 150, wantOk: true},
+		{name: "caught up", next: 151, latest: 150, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, ok := NextRange(tt.next, tt.latest, BatchSize)
+			if from != tt.wantFrom || to != tt.wantTo || ok != tt.wantOk {
+				t.Errorf("NextRange() = %v, %v, %v, want %v, %v, %v", from, to, ok, tt.wantFrom, tt.wantTo, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRollbackHeight(t *testing.T) {
+	tests := []struct {
+		name  string
+		next  uint64
+		start uint64
+		want  uint64
+	}{
+		{name: "rolls back depth", next: 1000, start: 0, want: 950},
+		{name: "stops at start height", next: 120, start: 100, want: 100},
+		{name: "near genesis", next: 10, start: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RollbackHeight(tt.next, ReorgDepth, tt.start); got != tt.want {
+				t.Errorf("RollbackHeight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetentionHeight(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		name   string
+		latest uint64
+		want   uint64
+	}{
+		// 6 second blocks keep 14400 blocks per day
+		{name: "keeps a day of blocks", latest: 1000000, want: 985600},
+		{name: "chain younger than the retention", latest: 10000, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			past := tt.latest - RetentionSampleBlocks
+			if got := RetentionHeight(past, 1000, tt.latest, 1000+6*RetentionSampleBlocks, day); got != tt.want {
+				t.Errorf("RetentionHeight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := RetentionHeight(100, 1000, 100, 1000, day); got != 0 {
+		t.Errorf("RetentionHeight() without elapsed blocks = %v, want 0", got)
+	}
+}
+
+func TestIndexedAddresses(t *testing.T) {
+	fpi := config.TokensInfo{
+		Comptroller: "0x5E23dC409Fc2F832f83CEc191E245A191a4bCc5C",
+		CTokens:     []config.Token{{Address: "0xEe602429Ef7eCe0a13e4FfE8dBC16e101049504C"}},
+		Pairs:       []config.Pair{{Address: "0x1D20635535307208919f0b67c3B2065965A85aA9"}},
+	}
+	want := []common.Address{
+		common.HexToAddress("0xEe602429Ef7eCe0a13e4FfE8dBC16e101049504C"),
+		common.HexToAddress("0x5E23dC409Fc2F832f83CEc191E245A191a4bCc5C"),
+		common.HexToAddress("0x1D20635535307208919f0b67c3B2065965A85aA9"),
+	}
+	if got := IndexedAddresses(fpi); !reflect.DeepEqual(got, want) {
+		t.Errorf("IndexedAddresses() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package queryengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+
+	"althea-api/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+)
+
+// returns the key of the sorted set holding events emitted by address
+func addressEventsKey(address string) string {
+	return config.IndexerEvents + ":" + address
+}
+
+// returns the key of the sorted set holding events of a single name emitted by address
+func namedEventsKey(address string, name string) string {
+	return addressEventsKey(address) + ":" + name
+}
+
+// gets the next block to index, ok is false if the indexer has not stored a height yet
+func (idx *Indexer) getNextBlock(ctx context.Context) (uint64, bool, error) {
+	val, err := idx.redisclient.Get(ctx, config.IndexerHeight).Result()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.New("getNextBlock: " + err.Error())
+	}
+	next, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, false, errors.New("getNextBlock: " + err.Error())
+	}
+	return next, true, nil
+}
+
+// gets the hash of the last indexed block, an empty hash is returned if none is stored
+func (idx *Indexer) getLastBlockHash(ctx context.Context) (common.Hash, error) {
+	val, err := idx.redisclient.Get(ctx, config.IndexerBlockHash).Result()
+	if err == redis.Nil {
+		return common.Hash{}, nil
+	}
+	if err != nil {
+		return common.Hash{}, errors.New("getLastBlockHash: " + err.Error())
+	}
+	return common.HexToHash(val), nil
+}
+
+// stores events of a block range along with the next block to index and the hash of the last indexed block,
+// everything is written in one transaction so a failure never leaves a partially indexed range
+func (idx *Indexer) storeEvents(ctx context.Context, events []IndexedEvent, next uint64, lastHash common.Hash) error {
+	_, err := idx.redisclient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, event := range events {
+			member, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			z := redis.Z{Score: float64(event.BlockNumber), Member: string(member)}
+			pipe.ZAdd(ctx, addressEventsKey(event.Address), z)
+			pipe.ZAdd(ctx, namedEventsKey(event.Address, event.Name), z)
+		}
+		pipe.Set(ctx, config.IndexerHeight, next, 0)
+		pipe.Set(ctx, config.IndexerBlockHash, lastHash.Hex(), 0)
+		return nil
+	})
+	if err != nil {
+		return errors.New("storeEvents: " + err.Error())
+	}
+	return nil
+}
+
+// removes events from block next onwards and resets the indexer to continue from next
+func (idx *Indexer) rollback(ctx context.Context, next uint64) error {
+	min := strconv.FormatUint(next, 10)
+	_, err := idx.redisclient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, address := range idx.addresses {
+			pipe.ZRemRangeByScore(ctx, addressEventsKey(address.Hex()), min, "+inf")
+			for name := range eventsABI.Events {
+				pipe.ZRemRangeByScore(ctx, namedEventsKey(address.Hex(), name), min, "+inf")
+			}
+		}
+		pipe.Set(ctx, config.IndexerHeight, next, 0)
+		pipe.Del(ctx, config.IndexerBlockHash)
+		return nil
+	})
+	if err != nil {
+		return errors.New("rollback: " + err.Error())
+	}
+	return nil
+}
+
+// removes events below block retentionHeight from the sets of every indexed contract
+func (idx *Indexer) prune(ctx context.Context, retentionHeight uint64) error {
+	max := "(" + strconv.FormatUint(retentionHeight, 10)
+	_, err := idx.redisclient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, address := range idx.addresses {
+			pipe.ZRemRangeByScore(ctx, addressEventsKey(address.Hex()), "-inf", max)
+			for name := range eventsABI.Events {
+				pipe.ZRemRangeByScore(ctx, namedEventsKey(address.Hex(), name), "-inf", max)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.New("prune: " + err.Error())
+	}
+	return nil
+}
+
+// GetEvents returns the events emitted by address between two block heights
+func GetEvents(ctx context.Context, address string, fromBlock uint64, toBlock uint64) ([]IndexedEvent, error) {
+	events, err := getEventsFromKey(ctx, addressEventsKey(common.HexToAddress(address).Hex()), fromBlock, toBlock)
+	if err != nil {
+		return nil, errors.New("GetEvents: " + err.Error())
+	}
+	return events, nil
+}
+
+// GetNamedEvents returns the events of a single name emitted by address between two block heights
+func GetNamedEvents(ctx context.Context, address string, name string, fromBlock uint64, toBlock uint64) ([]IndexedEvent, error) {
+	events, err := getEventsFromKey(ctx, namedEventsKey(common.HexToAddress(address).Hex(), name), fromBlock, toBlock)
+	if err != nil {
+		return nil, errors.New("GetNamedEvents: " + err.Error())
+	}
+	return events, nil
+}
+
+// returns the events stored in the sorted set at key between two block heights, ordered as they were emitted
+func getEventsFromKey(ctx context.Context, key string, fromBlock uint64, toBlock uint64) ([]IndexedEvent, error) {
+	members, err := config.RDB.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatUint(fromBlock, 10),
+		Max: strconv.FormatUint(toBlock, 10),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]IndexedEvent, 0, len(members))
+	for _, member := range members {
+		var event IndexedEvent
+		if err := json.Unmarshal([]byte(member), &event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	// members of a block share a score, so order them by their position in the block
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return events[i].LogIndex < events[j].LogIndex
+	})
+	return events, nil
+}
@@ -0,0 +1,327 @@
+package queryengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	types1 "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	query "github.com/cosmos/cosmos-sdk/types/query"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The cosmos-sdk version this api is built against only ships gov v1beta1 types,
+// so the gov v1 proposals query and its messages are encoded by hand here.
+// Field numbers follow cosmos/gov/v1/gov.proto and cosmos/gov/v1/query.proto.
+
+// GovV1QueryClient is the client for the gov v1 query service
+type GovV1QueryClient interface {
+	Proposals(ctx context.Context, in *GovV1ProposalsRequest, opts ...grpc.CallOption) (*GovV1ProposalsResponse, error)
+}
+
+type govV1QueryClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGovV1QueryClient(cc *grpc.ClientConn) GovV1QueryClient {
+	return &govV1QueryClient{cc}
+}
+
+func (c *govV1QueryClient) Proposals(ctx context.Context, in *GovV1ProposalsRequest, opts ...grpc.CallOption) (*GovV1ProposalsResponse, error) {
+	out := new(GovV1ProposalsResponse)
+	err := c.cc.Invoke(ctx, "/cosmos.gov.v1.Query/Proposals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GovV1ProposalsRequest is the request type for the gov v1 Query/Proposals method
+type GovV1ProposalsRequest struct {
+	Pagination *query.PageRequest
+}
+
+func (m *GovV1ProposalsRequest) Reset()         { *m = GovV1ProposalsRequest{} }
+func (m *GovV1ProposalsRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*GovV1ProposalsRequest) ProtoMessage()    {}
+
+func (m *GovV1ProposalsRequest) Marshal() ([]byte, error) {
+	var b []byte
+	if m.Pagination != nil {
+		page, err := m.Pagination.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendBytes(b, page)
+	}
+	return b, nil
+}
+
+// GovV1ProposalsResponse is the response type for the gov v1 Query/Proposals method
+type GovV1ProposalsResponse struct {
+	Proposals  []GovV1Proposal
+	Pagination *query.PageResponse
+}
+
+func (m *GovV1ProposalsResponse) Reset()         { *m = GovV1ProposalsResponse{} }
+func (m *GovV1ProposalsResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*GovV1ProposalsResponse) ProtoMessage()    {}
+
+func (m *GovV1ProposalsResponse) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch num {
+		case 1:
+			var proposal GovV1Proposal
+			if err := proposal.Unmarshal(value); err != nil {
+				return err
+			}
+			m.Proposals = append(m.Proposals, proposal)
+		case 2:
+			m.Pagination = &query.PageResponse{}
+			return m.Pagination.Unmarshal(value)
+		}
+		return nil
+	})
+}
+
+// GovV1Proposal is a gov v1 proposal
+type GovV1Proposal struct {
+	Id               uint64
+	Messages         []*types1.Any
+	Status           gov.ProposalStatus
+	FinalTallyResult gov.TallyResult
+	SubmitTime       time.Time
+	DepositEndTime   time.Time
+	TotalDeposit     sdk.Coins
+	VotingStartTime  time.Time
+	VotingEndTime    time.Time
+	Metadata         string
+	Title            string
+	Summary          string
+	Proposer         string
+	Expedited        bool
+}
+
+func (m *GovV1Proposal) Unmarshal(b []byte) error {
+	return unmarshalFields(b, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		var err error
+		switch num {
+		case 1:
+			m.Id, err = unmarshalVarint(typ, value)
+		case 2:
+			msg := &types1.Any{}
+			err = msg.Unmarshal(value)
+			m.Messages = append(m.Messages, msg)
+		case 3:
+			var status uint64
+			status, err = unmarshalVarint(typ, value)
+			m.Status = gov.ProposalStatus(status)
+		case 4:
+			m.FinalTallyResult, err = unmarshalGovV1TallyResult(value)
+		case 5:
+			m.SubmitTime, err = unmarshalTimestamp(value)
+		case 6:
+			m.DepositEndTime, err = unmarshalTimestamp(value)
+		case 7:
+			var coin sdk.Coin
+			err = coin.Unmarshal(value)
+			m.TotalDeposit = append(m.TotalDeposit, coin)
+		case 8:
+			m.VotingStartTime, err = unmarshalTimestamp(value)
+		case 9:
+			m.VotingEndTime, err = unmarshalTimestamp(value)
+		case 10:
+			m.Metadata = string(value)
+		case 11:
+			m.Title = string(value)
+		case 12:
+			m.Summary = string(value)
+		case 13:
+			m.Proposer = string(value)
+		case 14:
+			var expedited uint64
+			expedited, err = unmarshalVarint(typ, value)
+			m.Expedited = expedited != 0
+		}
+		return err
+	})
+}
+
+// gov v1 tally results hold the counts as strings
+func unmarshalGovV1TallyResult(b []byte) (gov.TallyResult, error) {
+	tally := gov.NewTallyResult(sdk.ZeroInt(), sdk.ZeroInt(), sdk.ZeroInt(), sdk.ZeroInt())
+	err := unmarshalFields(b, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		count, ok := sdk.NewIntFromString(string(value))
+		if !ok {
+			return fmt.Errorf("invalid tally count: %s", string(value))
+		}
+		switch num {
+		case 1:
+			tally.Yes = count
+		case 2:
+			tally.Abstain = count
+		case 3:
+			tally.No = count
+		case 4:
+			tally.NoWithVeto = count
+		}
+		return nil
+	})
+	return tally, err
+}
+
+func unmarshalTimestamp(b []byte) (time.Time, error) {
+	var seconds, nanos uint64
+	err := unmarshalFields(b, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		var err error
+		switch num {
+		case 1:
+			seconds, err = unmarshalVarint(typ, value)
+		case 2:
+			nanos, err = unmarshalVarint(typ, value)
+		}
+		return err
+	})
+	return time.Unix(int64(seconds), int64(nanos)).UTC(), err
+}
+
+func unmarshalVarint(typ protowire.Type, value []byte) (uint64, error) {
+	if typ != protowire.VarintType {
+		return 0, fmt.Errorf("unexpected wire type %d for varint field", typ)
+	}
+	v, n := protowire.ConsumeVarint(value)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return v, nil
+}
+
+// unmarshalFields walks the fields of a protobuf message and calls handle with the
+// raw value of each field (the varint bytes for varints, the payload for length delimited fields)
+func unmarshalFields(b []byte, handle func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		var value []byte
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			value, b = v, b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			value, b = b[:n], b[n:]
+		}
+		if err := handle(num, typ, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get all gov v1 proposals with their messages decoded
+// gov params and bonded tokens are needed to compute tally status and deposit progress
+func GetAllV1Proposals(ctx context.Context, queryClient GovV1QueryClient, govQueryClient gov.QueryClient, govParams GovParams, bondedTokens sdk.Int) ([]Proposal, error) {
+	allProposals := []Proposal{}
+	now := time.Now()
+	var nextKey []byte
+	for {
+		resp, err := queryClient.Proposals(ctx, &GovV1ProposalsRequest{
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, proposal := range resp.Proposals {
+			votes := proposal.FinalTallyResult
+			// if vote is still ongoing, query the current tally
+			if proposal.Status == gov.StatusVotingPeriod {
+				tallyResp, err := govQueryClient.TallyResult(ctx, &gov.QueryTallyResultRequest{
+					ProposalId: proposal.Id,
+				})
+				if err == nil {
+					votes = tallyResp.Tally
+				}
+			}
+			messages := []json.RawMessage{}
+			for _, msg := range proposal.Messages {
+				messages = append(messages, AnyToJSON(msg))
+			}
+			proposalResponse := Proposal{
+				ProposalId:      proposal.Id,
+				Title:           proposal.Title,
+				Summary:         proposal.Summary,
+				Metadata:        proposal.Metadata,
+				Messages:        messages,
+				Proposer:        proposal.Proposer,
+				Expedited:       proposal.Expedited,
+				Status:          proposal.Status.String(),
+				FinalVote:       votes,
+				SubmitTime:      proposal.SubmitTime,
+				DepositEndTime:  proposal.DepositEndTime,
+				TotalDeposit:    proposal.TotalDeposit,
+				VotingStartTime: proposal.VotingStartTime,
+				VotingEndTime:   proposal.VotingEndTime,
+				TallyStatus:     CalculateTallyStatus(votes, govParams.tallyParams, bondedTokens, proposal.Status == gov.StatusVotingPeriod),
+			}
+			SetProposalProgress(&proposalResponse, govParams.minDeposit, now)
+			allProposals = append(allProposals, proposalResponse)
+		}
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+	return allProposals, nil
+}
+
+// MergeProposals merges gov v1 proposals into v1beta1 proposals by proposal id.
+// v1beta1 proposals keep their decoded content and gain the v1 only fields,
+// proposals only known to gov v1 are added. The result is sorted by proposal id.
+func MergeProposals(v1beta1Proposals []Proposal, v1Proposals []Proposal) []Proposal {
+	merged := make(map[uint64]Proposal)
+	for _, proposal := range v1beta1Proposals {
+		merged[proposal.ProposalId] = proposal
+	}
+	for _, v1Proposal := range v1Proposals {
+		proposal, ok := merged[v1Proposal.ProposalId]
+		if !ok {
+			merged[v1Proposal.ProposalId] = v1Proposal
+			continue
+		}
+		proposal.Messages = v1Proposal.Messages
+		proposal.Metadata = v1Proposal.Metadata
+		proposal.Summary = v1Proposal.Summary
+		proposal.Proposer = v1Proposal.Proposer
+		proposal.Expedited = v1Proposal.Expedited
+		if proposal.Title == "" {
+			proposal.Title = v1Proposal.Title
+		}
+		merged[v1Proposal.ProposalId] = proposal
+	}
+	allProposals := make([]Proposal, 0, len(merged))
+	for _, proposal := range merged {
+		allProposals = append(allProposals, proposal)
+	}
+	sort.Slice(allProposals, func(i, j int) bool {
+		return allProposals[i].ProposalId < allProposals[j].ProposalId
+	})
+	return allProposals
+}
@@ -0,0 +1,105 @@
+package queryengine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/proto"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func appendBytesField(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+func appendVarintField(b []byte, num protowire.Number, value uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+func TestGovV1ProposalsResponse_Unmarshal(t *testing.T) {
+	submitTime := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	var timestamp []byte
+	timestamp = appendVarintField(timestamp, 1, uint64(submitTime.Unix()))
+	timestamp = appendVarintField(timestamp, 2, uint64(submitTime.Nanosecond()))
+
+	var tally []byte
+	tally = appendBytesField(tally, 1, []byte("30"))
+	tally = appendBytesField(tally, 2, []byte("20"))
+	tally = appendBytesField(tally, 3, []byte("10"))
+	tally = appendBytesField(tally, 4, []byte("0"))
+
+	depositCoin := sdk.NewCoin("aalthea", sdk.NewInt(100))
+	deposit, _ := depositCoin.Marshal()
+
+	var msg []byte
+	msg = appendBytesField(msg, 1, []byte("/cosmos.gov.v1.MsgExecLegacyContent"))
+
+	var proposal []byte
+	proposal = appendVarintField(proposal, 1, 7)
+	proposal = appendBytesField(proposal, 2, msg)
+	proposal = appendVarintField(proposal, 3, uint64(gov.StatusPassed))
+	proposal = appendBytesField(proposal, 4, tally)
+	proposal = appendBytesField(proposal, 5, timestamp)
+	proposal = appendBytesField(proposal, 7, deposit)
+	proposal = appendBytesField(proposal, 10, []byte("ipfs://metadata"))
+	proposal = appendBytesField(proposal, 11, []byte("title"))
+	proposal = appendBytesField(proposal, 12, []byte("summary"))
+	proposal = appendBytesField(proposal, 13, []byte("althea1proposer"))
+	proposal = appendVarintField(proposal, 14, 1)
+	// unknown fields are skipped
+	proposal = appendVarintField(proposal, 99, 1)
+
+	var resp []byte
+	resp = appendBytesField(resp, 1, proposal)
+
+	// decode through the grpc codec like a real response
+	got := &GovV1ProposalsResponse{}
+	if err := encoding.GetCodec("proto").Unmarshal(resp, got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(got.Proposals) != 1 {
+		t.Fatalf("Unmarshal() got %d proposals, want 1", len(got.Proposals))
+	}
+	p := got.Proposals[0]
+	if p.Id != 7 || p.Status != gov.StatusPassed || p.Title != "title" || p.Summary != "summary" ||
+		p.Metadata != "ipfs://metadata" || p.Proposer != "althea1proposer" || !p.Expedited {
+		t.Errorf("Unmarshal() proposal = %+v", p)
+	}
+	if !p.SubmitTime.Equal(submitTime) {
+		t.Errorf("Unmarshal() submit time = %v, want %v", p.SubmitTime, submitTime)
+	}
+	if !p.FinalTallyResult.Equals(gov.NewTallyResult(sdk.NewInt(30), sdk.NewInt(20), sdk.NewInt(10), sdk.ZeroInt())) {
+		t.Errorf("Unmarshal() tally = %v", p.FinalTallyResult)
+	}
+	if !p.TotalDeposit.IsEqual(sdk.NewCoins(sdk.NewCoin("aalthea", sdk.NewInt(100)))) {
+		t.Errorf("Unmarshal() deposit = %v", p.TotalDeposit)
+	}
+	if len(p.Messages) != 1 || p.Messages[0].TypeUrl != "/cosmos.gov.v1.MsgExecLegacyContent" {
+		t.Errorf("Unmarshal() messages = %v", p.Messages)
+	}
+}
+
+func TestMergeProposals(t *testing.T) {
+	v1beta1Proposals := []Proposal{
+		{ProposalId: 2, Title: "legacy title"},
+		{ProposalId: 1, Title: "first"},
+	}
+	v1Proposals := []Proposal{
+		{ProposalId: 2, Title: "v1 title", Summary: "summary", Proposer: "althea1proposer"},
+		{ProposalId: 3, Title: "v1 only", Expedited: true},
+	}
+	want := []Proposal{
+		{ProposalId: 1, Title: "first"},
+		{ProposalId: 2, Title: "legacy title", Summary: "summary", Proposer: "althea1proposer"},
+		{ProposalId: 3, Title: "v1 only", Expedited: true},
+	}
+	if got := MergeProposals(v1beta1Proposals, v1Proposals); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeProposals() = %+v, want %+v", got, want)
+	}
+}
@@ -4,19 +4,40 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"althea-api/config"
+	"althea-api/multicall"
+
+	cantoConfig "github.com/Canto-Network/Canto/v6/cmd/config"
 	csr "github.com/Canto-Network/Canto/v6/x/csr/types"
+	erc20 "github.com/Canto-Network/Canto/v6/x/erc20/types"
+	types1 "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	query "github.com/cosmos/cosmos-sdk/types/query"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
 	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
 	inflation "github.com/cosmos/cosmos-sdk/x/mint/types"
+	slashing "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/rs/zerolog/log"
+	tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
 )
 
+// number of blocks the average block time is measured over,
+// the average is published to cache and also annualizes lending rates in the contract engine
+const BlockTimeWindow = 1000
+
 // STAKING
 type Validator struct {
 	// operator_address defines the address of the validator's operator; bech encoded in JSON.
@@ -31,11 +52,37 @@ type Validator struct {
 	Description staking.Description `json:"description"`
 	// commission defines the commission rate.
 	Commission string `json:"commission"`
+	// apr defines the staking APR earned by delegators of this validator, net of commission.
+	APR string `json:"apr"`
+	// consensus_address is the bech32 consensus address of the validator's consensus public key.
+	ConsensusAddress string `json:"consensus_address"`
+}
+
+// pubKeyRegistry unpacks validator consensus public keys
+var pubKeyRegistry = newPubKeyInterfaceRegistry()
+
+func newPubKeyInterfaceRegistry() types1.InterfaceRegistry {
+	registry := types1.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+	return registry
+}
+
+// get the bech32 consensus address of a validator, validators from grpc responses
+// hold their consensus public key packed, so it is unpacked first
+func getConsensusAddress(validator staking.Validator) (string, error) {
+	if err := validator.UnpackInterfaces(pubKeyRegistry); err != nil {
+		return "", err
+	}
+	consAddr, err := validator.GetConsAddr()
+	if err != nil {
+		return "", err
+	}
+	return bech32.ConvertAndEncode(cantoConfig.Bech32PrefixConsAddr, consAddr)
 }
 
 // get all Validators for staking
 // will return full response string and mapping of operator address to response string
-func GetValidators(ctx context.Context, queryClient staking.QueryClient) ([]Validator, map[string]string, error) {
+func GetValidators(ctx context.Context, queryClient staking.QueryClient, stakingApr sdk.Dec) ([]Validator, map[string]string, error) {
 	respValidators, err := queryClient.Validators(ctx, &staking.QueryValidatorsRequest{
 		Pagination: &query.PageRequest{
 			Limit: 1000,
@@ -54,34 +101,270 @@ func GetValidators(ctx context.Context, queryClient staking.QueryClient) ([]Vali
 			Tokens:          validator.Tokens.String(),
 			Description:     validator.Description,
 			Commission:      validator.Commission.CommissionRates.Rate.String(),
+			APR:             CalculateValidatorAPR(stakingApr, validator.Commission.CommissionRates.Rate).String(),
+		}
+		consensusAddress, err := getConsensusAddress(validator)
+		if err != nil {
+			log.Warn().Err(err).Str("func", "GetValidators").Msgf("Failed to get consensus address of %s", validator.OperatorAddress)
 		}
+		valResponse.ConsensusAddress = consensusAddress
 		*allValidators = append(*allValidators, valResponse)
 		validatorMap[validator.OperatorAddress] = GeneralResultToString(valResponse)
 	}
 	return *allValidators, validatorMap, nil
 }
-func GetStakingAPR(ctx context.Context, stakingQueryClient staking.QueryClient, inflationQueryClient inflation.QueryClient) (sdk.Dec, error) {
-    // Fetch pool information
-    poolResp, err := stakingQueryClient.Pool(ctx, &staking.QueryPoolRequest{})
-    if err != nil {
-        return sdk.Dec{}, err // Return error if fetching pool fails
-    }
-    
-    // Fetch mint provision information
-    mintProvisionResp, err := inflationQueryClient.AnnualProvisions(ctx, &inflation.QueryAnnualProvisionsRequest{})
-    if err != nil {
-        return sdk.Dec{}, err // Return error if fetching mint provision fails
-    }
-    
-    bondedTokensDec := sdk.NewDecFromInt(poolResp.Pool.BondedTokens)
-    if bondedTokensDec.IsZero() {
-        return sdk.NewDec(0), nil // Avoid division by zero, not necessarily an error
-    }
-    
-    mintProvisionAmount := mintProvisionResp.AnnualProvisions
-    apr := mintProvisionAmount.Quo(bondedTokensDec).MulInt64(100) // Adjusted to multiply by 100 for percentage
-    
-    return apr, nil // Return calculated APR and no error
+// get the network staking APR net of the distribution module's community tax
+func GetStakingAPR(ctx context.Context, stakingQueryClient staking.QueryClient, inflationQueryClient inflation.QueryClient, distributionQueryClient distrtypes.QueryClient) (sdk.Dec, error) {
+	// Fetch pool information
+	poolResp, err := stakingQueryClient.Pool(ctx, &staking.QueryPoolRequest{})
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	// Fetch mint provision information
+	mintProvisionResp, err := inflationQueryClient.AnnualProvisions(ctx, &inflation.QueryAnnualProvisionsRequest{})
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	// Fetch community tax from distribution params
+	distrParamsResp, err := distributionQueryClient.Params(ctx, &distrtypes.QueryParamsRequest{})
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	return CalculateStakingAPR(poolResp, mintProvisionResp, distrParamsResp.Params.CommunityTax), nil
+}
+
+// VALIDATOR DELEGATIONS
+
+// get all delegations made to a validator, paging through the full result set
+// delegations are sorted by balance amount, largest first
+func GetValidatorDelegations(ctx context.Context, queryClient staking.QueryClient, validatorAddress string, metadata DenomMetadataMap) ([]DelegationInfo, error) {
+	delegations := []DelegationInfo{}
+	var nextKey []byte
+	for {
+		resp, err := queryClient.ValidatorDelegations(ctx, &staking.QueryValidatorDelegationsRequest{
+			ValidatorAddr: validatorAddress,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch validator delegations: %w", err)
+		}
+		for _, del := range resp.GetDelegationResponses() {
+			delegations = append(delegations, DelegationInfo{
+				Delegation: Delegation{
+					DelegatorAddress: del.Delegation.DelegatorAddress,
+					ValidatorAddress: del.Delegation.ValidatorAddress,
+					Shares:           del.Delegation.Shares.String(),
+				},
+				Balance: NewBalanceFromCoin(del.Balance, metadata),
+			})
+		}
+		if resp.GetPagination() == nil || len(resp.GetPagination().NextKey) == 0 {
+			break
+		}
+		nextKey = resp.GetPagination().NextKey
+	}
+	sort.SliceStable(delegations, func(i, j int) bool {
+		return amountGreaterThan(delegations[i].Balance.Amount, delegations[j].Balance.Amount)
+	})
+	return delegations, nil
+}
+
+// get the denom of staking tokens from the staking params
+func GetBondDenom(ctx context.Context, queryClient staking.QueryClient) (string, error) {
+	resp, err := queryClient.Params(ctx, &staking.QueryParamsRequest{})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch staking params: %w", err)
+	}
+	return resp.Params.BondDenom, nil
+}
+
+// get all unbonding delegation entries from a validator, paging through the full result set
+// entries are sorted by remaining balance, largest first
+func GetValidatorUnbondings(ctx context.Context, queryClient staking.QueryClient, validatorAddress string, bondDenom string, metadata DenomMetadataMap) ([]UnbondingDelegation, error) {
+	unbondings := []UnbondingDelegation{}
+	var nextKey []byte
+	for {
+		resp, err := queryClient.ValidatorUnbondingDelegations(ctx, &staking.QueryValidatorUnbondingDelegationsRequest{
+			ValidatorAddr: validatorAddress,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch validator unbonding delegations: %w", err)
+		}
+		for _, unbond := range resp.GetUnbondingResponses() {
+			for _, entry := range unbond.Entries {
+				unbondings = append(unbondings, UnbondingDelegation{
+					DelegatorAddress: unbond.DelegatorAddress,
+					ValidatorAddress: unbond.ValidatorAddress,
+					CreationHeight:   entry.CreationHeight,
+					CompletionTime:   entry.CompletionTime,
+					InitialBalance:   NewBalanceFromCoin(sdk.NewCoin(bondDenom, entry.InitialBalance), metadata),
+					Balance:          NewBalanceFromCoin(sdk.NewCoin(bondDenom, entry.Balance), metadata),
+				})
+			}
+		}
+		if resp.GetPagination() == nil || len(resp.GetPagination().NextKey) == 0 {
+			break
+		}
+		nextKey = resp.GetPagination().NextKey
+	}
+	sort.SliceStable(unbondings, func(i, j int) bool {
+		return amountGreaterThan(unbondings[i].Balance.Amount, unbondings[j].Balance.Amount)
+	})
+	return unbondings, nil
+}
+
+// VALIDATOR EVENTS
+
+// validator event types
+const (
+	EventJailed            = "JAILED"
+	EventUnjailed          = "UNJAILED"
+	EventTombstoned        = "TOMBSTONED"
+	EventStatusChanged     = "STATUS_CHANGED"
+	EventCommissionChanged = "COMMISSION_CHANGED"
+)
+
+// ValidatorEventTypes are all validator event types
+var ValidatorEventTypes = []string{EventJailed, EventUnjailed, EventTombstoned, EventStatusChanged, EventCommissionChanged}
+
+// IsValidatorEventType checks if the given type is a validator event type
+func IsValidatorEventType(eventType string) bool {
+	for _, t := range ValidatorEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatorEvent is a change of a validator seen between two ticks
+type ValidatorEvent struct {
+	Validator string `json:"validator"`
+	Moniker   string `json:"moniker"`
+	Type      string `json:"type"`
+	// from and to are the previous and new status or commission rate, empty for jailing and tombstoning
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// height and time are of the latest block when the change was detected,
+	// the change itself happened after the previous tick and at or before this block
+	Height int64     `json:"height"`
+	Time   time.Time `json:"time"`
+}
+
+// ValidatorSnapshot is the state of a validator that validator events are derived from
+type ValidatorSnapshot struct {
+	Moniker    string `json:"moniker"`
+	Jailed     bool   `json:"jailed"`
+	Tombstoned bool   `json:"tombstoned"`
+	Status     string `json:"status"`
+	Commission string `json:"commission"`
+}
+
+// get the signing infos of all validators, mapped by bech32 consensus address
+func GetSigningInfos(ctx context.Context, queryClient slashing.QueryClient) (map[string]slashing.ValidatorSigningInfo, error) {
+	signingInfos := make(map[string]slashing.ValidatorSigningInfo)
+	var nextKey []byte
+	for {
+		resp, err := queryClient.SigningInfos(ctx, &slashing.QuerySigningInfosRequest{
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch signing infos: %w", err)
+		}
+		for _, info := range resp.GetInfo() {
+			signingInfos[info.Address] = info
+		}
+		if resp.GetPagination() == nil || len(resp.GetPagination().NextKey) == 0 {
+			break
+		}
+		nextKey = resp.GetPagination().NextKey
+	}
+	return signingInfos, nil
+}
+
+// NewValidatorSnapshots returns a snapshot of every validator, mapped by operator address
+func NewValidatorSnapshots(validators []Validator, signingInfos map[string]slashing.ValidatorSigningInfo) map[string]ValidatorSnapshot {
+	snapshots := make(map[string]ValidatorSnapshot)
+	for _, validator := range validators {
+		snapshots[validator.OperatorAddress] = ValidatorSnapshot{
+			Moniker:    validator.Description.Moniker,
+			Jailed:     validator.Jailed,
+			Tombstoned: signingInfos[validator.ConsensusAddress].Tombstoned,
+			Status:     validator.Status,
+			Commission: validator.Commission,
+		}
+	}
+	return snapshots
+}
+
+// DiffValidatorSnapshots returns the events between the previous and current validator snapshots,
+// sorted by validator. Validators missing from the previous snapshots have no events.
+func DiffValidatorSnapshots(previous map[string]ValidatorSnapshot, current map[string]ValidatorSnapshot, height int64, now time.Time) []ValidatorEvent {
+	events := []ValidatorEvent{}
+	for operator, curr := range current {
+		prev, ok := previous[operator]
+		if !ok {
+			continue
+		}
+		newEvent := func(eventType string, from string, to string) ValidatorEvent {
+			return ValidatorEvent{
+				Validator: operator,
+				Moniker:   curr.Moniker,
+				Type:      eventType,
+				From:      from,
+				To:        to,
+				Height:    height,
+				Time:      now,
+			}
+		}
+		if !prev.Jailed && curr.Jailed {
+			events = append(events, newEvent(EventJailed, "", ""))
+		}
+		if prev.Jailed && !curr.Jailed {
+			events = append(events, newEvent(EventUnjailed, "", ""))
+		}
+		if !prev.Tombstoned && curr.Tombstoned {
+			events = append(events, newEvent(EventTombstoned, "", ""))
+		}
+		if prev.Status != curr.Status {
+			events = append(events, newEvent(EventStatusChanged, prev.Status, curr.Status))
+		}
+		if prev.Commission != curr.Commission {
+			events = append(events, newEvent(EventCommissionChanged, prev.Commission, curr.Commission))
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Validator < events[j].Validator
+	})
+	return events
+}
+
+// FilterValidatorEvents returns the events of a validator and type, empty filters match every event
+func FilterValidatorEvents(events []ValidatorEvent, validator string, eventType string) []ValidatorEvent {
+	filtered := []ValidatorEvent{}
+	for _, event := range events {
+		if validator != "" && event.Validator != validator {
+			continue
+		}
+		if eventType != "" && event.Type != eventType {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
 }
 
 // USER DELEGATIONS
@@ -89,7 +372,12 @@ func GetStakingAPR(ctx context.Context, stakingQueryClient staking.QueryClient,
 type DelegationResponse struct {
 	Delegations          []DelegationInfo      `json:"delegations"`
 	UnbondingDelegations []UnbondingDelegation `json:"unbondingDelegations"`
+	Redelegations        []Redelegation        `json:"redelegations"`
 	Rewards              RewardsInfo           `json:"rewards"`
+	// withdrawAddress is the address staking rewards are withdrawn to
+	WithdrawAddress string `json:"withdrawAddress"`
+	// liquidBalance is the delegator's spendable bank balance
+	LiquidBalance []Balance `json:"liquidBalance"`
 }
 
 
@@ -107,9 +395,12 @@ type Delegation struct {
 }
 
 // Balance holds the denomination and amount of tokens.
+// amount is in the base denom, displayAmount is scaled to the display denom from bank metadata.
 type Balance struct {
-	Denom  string `json:"denom"`
-	Amount string `json:"amount"`
+	Denom         string `json:"denom"`
+	Amount        string `json:"amount"`
+	DisplayDenom  string `json:"display_denom"`
+	DisplayAmount string `json:"display_amount"`
 }
 
 // RewardsInfo holds information about rewards.
@@ -123,8 +414,20 @@ type UnbondingDelegation struct {
     ValidatorAddress   string `json:"validator_address"`
     CreationHeight     int64  `json:"creation_height"`
     CompletionTime     time.Time `json:"completion_time"`
-    InitialBalance     string `json:"initial_balance"`
-    Balance            string `json:"balance"`
+    InitialBalance     Balance `json:"initial_balance"`
+    Balance            Balance `json:"balance"`
+}
+
+// Redelegation holds a single active redelegation entry. While it is active the
+// delegator cannot redelegate again from the destination validator.
+type Redelegation struct {
+	DelegatorAddress    string    `json:"delegator_address"`
+	ValidatorSrcAddress string    `json:"validator_src_address"`
+	ValidatorDstAddress string    `json:"validator_dst_address"`
+	CreationHeight      int64     `json:"creation_height"`
+	CompletionTime      time.Time `json:"completion_time"`
+	InitialBalance      Balance   `json:"initial_balance"`
+	Balance             Balance   `json:"balance"`
 }
 
 func (r RewardsInfo) MarshalJSON() ([]byte, error) {
@@ -146,8 +449,8 @@ type ValidatorReward struct {
 
 
 
-// FetchUserDelegations fetches delegations for a specific user.
-func FetchUserDelegations(ctx context.Context, stakingQueryClient staking.QueryClient, distributionQueryClient distrtypes.QueryClient, delegatorAddress string) (*DelegationResponse, error) {
+// FetchUserDelegations fetches delegations for a specific user, amounts are converted to display units with the given denom metadata.
+func FetchUserDelegations(ctx context.Context, stakingQueryClient staking.QueryClient, distributionQueryClient distrtypes.QueryClient, bankQueryClient bank.QueryClient, delegatorAddress string, metadata DenomMetadataMap) (*DelegationResponse, error) {
     response := &DelegationResponse{}
 
     // Fetch delegations
@@ -159,6 +462,12 @@ func FetchUserDelegations(ctx context.Context, stakingQueryClient staking.QueryC
         return nil, fmt.Errorf("failed to fetch delegations: %w", err)
     }
 
+    // unbonding and redelegation entries only hold amounts of the bond denom
+    bondDenom, err := GetBondDenom(ctx, stakingQueryClient)
+    if err != nil {
+        return nil, err
+    }
+
     // Handle delegations response
     for _, del := range delegationResp.DelegationResponses {
         response.Delegations = append(response.Delegations, DelegationInfo{
@@ -167,10 +476,7 @@ func FetchUserDelegations(ctx context.Context, stakingQueryClient staking.QueryC
                 ValidatorAddress: del.Delegation.ValidatorAddress,
                 Shares:           del.Delegation.Shares.String(),
             },
-            Balance: Balance{
-                Denom:  del.Balance.Denom,
-                Amount: del.Balance.Amount.String(),
-            },
+            Balance: NewBalanceFromCoin(del.Balance, metadata),
         })
     }
 
@@ -191,8 +497,8 @@ func FetchUserDelegations(ctx context.Context, stakingQueryClient staking.QueryC
                 ValidatorAddress: unbond.ValidatorAddress,
                 CreationHeight:   entry.CreationHeight,
                 CompletionTime:   entry.CompletionTime,
-                InitialBalance:   entry.InitialBalance.String(),
-                Balance:          entry.Balance.String(),
+                InitialBalance:   NewBalanceFromCoin(sdk.NewCoin(bondDenom, entry.InitialBalance), metadata),
+                Balance:          NewBalanceFromCoin(sdk.NewCoin(bondDenom, entry.Balance), metadata),
             })
         }
     }
@@ -202,6 +508,71 @@ func FetchUserDelegations(ctx context.Context, stakingQueryClient staking.QueryC
         response.UnbondingDelegations = nil
     }
 
+    // Fetch active redelegations, paging through the full result set
+    response.Redelegations = []Redelegation{}
+    var nextKey []byte
+    for {
+        redelegationResp, err := stakingQueryClient.Redelegations(ctx, &staking.QueryRedelegationsRequest{
+            DelegatorAddr: delegatorAddress,
+            Pagination: &query.PageRequest{
+                Key:   nextKey,
+                Limit: 1000,
+            },
+        })
+        if err != nil {
+            return nil, fmt.Errorf("failed to fetch redelegations: %w", err)
+        }
+        for _, redel := range redelegationResp.RedelegationResponses {
+            for _, entry := range redel.Entries {
+                response.Redelegations = append(response.Redelegations, Redelegation{
+                    DelegatorAddress:    redel.Redelegation.DelegatorAddress,
+                    ValidatorSrcAddress: redel.Redelegation.ValidatorSrcAddress,
+                    ValidatorDstAddress: redel.Redelegation.ValidatorDstAddress,
+                    CreationHeight:      entry.RedelegationEntry.CreationHeight,
+                    CompletionTime:      entry.RedelegationEntry.CompletionTime,
+                    InitialBalance:      NewBalanceFromCoin(sdk.NewCoin(bondDenom, entry.RedelegationEntry.InitialBalance), metadata),
+                    Balance:             NewBalanceFromCoin(sdk.NewCoin(bondDenom, entry.Balance), metadata),
+                })
+            }
+        }
+        if redelegationResp.GetPagination() == nil || len(redelegationResp.GetPagination().NextKey) == 0 {
+            break
+        }
+        nextKey = redelegationResp.GetPagination().NextKey
+    }
+
+    // Fetch withdraw address
+    withdrawResp, err := distributionQueryClient.DelegatorWithdrawAddress(ctx, &distrtypes.QueryDelegatorWithdrawAddressRequest{
+        DelegatorAddress: delegatorAddress,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch withdraw address: %w", err)
+    }
+    response.WithdrawAddress = withdrawResp.WithdrawAddress
+
+    // Fetch liquid balance, paging through the full result set
+    response.LiquidBalance = []Balance{}
+    nextKey = nil
+    for {
+        balanceResp, err := bankQueryClient.SpendableBalances(ctx, &bank.QuerySpendableBalancesRequest{
+            Address: delegatorAddress,
+            Pagination: &query.PageRequest{
+                Key:   nextKey,
+                Limit: 1000,
+            },
+        })
+        if err != nil {
+            return nil, fmt.Errorf("failed to fetch liquid balance: %w", err)
+        }
+        for _, coin := range balanceResp.Balances {
+            response.LiquidBalance = append(response.LiquidBalance, NewBalanceFromCoin(coin, metadata))
+        }
+        if balanceResp.GetPagination() == nil || len(balanceResp.GetPagination().NextKey) == 0 {
+            break
+        }
+        nextKey = balanceResp.GetPagination().NextKey
+    }
+
     // Fetch rewards
     rewardsResp, err := distributionQueryClient.DelegationTotalRewards(ctx, &distrtypes.QueryDelegationTotalRewardsRequest{
         DelegatorAddress: delegatorAddress,
@@ -214,10 +585,7 @@ func FetchUserDelegations(ctx context.Context, stakingQueryClient staking.QueryC
     for _, reward := range rewardsResp.Rewards {
         var validatorRewards []Balance
         for _, valReward := range reward.Reward {
-            validatorRewards = append(validatorRewards, Balance{
-                Denom:  valReward.Denom,
-                Amount: valReward.Amount.String(),
-            })
+            validatorRewards = append(validatorRewards, NewBalanceFromDecCoin(valReward, metadata))
         }
         response.Rewards.Rewards = append(response.Rewards.Rewards, ValidatorReward{
             ValidatorAddress: reward.ValidatorAddress,
@@ -225,26 +593,14 @@ func FetchUserDelegations(ctx context.Context, stakingQueryClient staking.QueryC
         })
     }
 
-    // Calculate total rewards
-    var total []Balance
-    for _, reward := range response.Rewards.Rewards {
-        for _, valReward := range reward.Reward {
-            found := false
-            for i, totalReward := range total {
-                if totalReward.Denom == valReward.Denom {
-                    amount, _ := strconv.ParseFloat(totalReward.Amount, 64)
-                    rewardAmount, _ := strconv.ParseFloat(valReward.Amount, 64)
-                    total[i].Amount = fmt.Sprintf("%f", amount+rewardAmount)
-                    found = true
-                    break
-                }
-            }
-            if !found {
-                total = append(total, valReward)
-            }
-        }
+    // Calculate total rewards with exact decimal arithmetic
+    totalRewards := sdk.NewDecCoins()
+    for _, reward := range rewardsResp.Rewards {
+        totalRewards = totalRewards.Add(reward.Reward...)
+    }
+    for _, coin := range totalRewards {
+        response.Rewards.Total = append(response.Rewards.Total, NewBalanceFromDecCoin(coin, metadata))
     }
-    response.Rewards.Total = total
 
     return response, nil
 }
@@ -260,6 +616,18 @@ type Proposal struct {
 	Title string `json:"title"`
 	// description of the proposal
 	Description string `json:"description"`
+	// content is the full decoded proposal content (v1beta1 proposals)
+	Content json.RawMessage `json:"content"`
+	// summary of the proposal (gov v1 proposals)
+	Summary string `json:"summary"`
+	// metadata is the arbitrary metadata attached to the proposal (gov v1 proposals)
+	Metadata string `json:"metadata"`
+	// messages are the decoded messages executed if the proposal passes (gov v1 proposals)
+	Messages []json.RawMessage `json:"messages"`
+	// proposer is the address of the proposal submitter (gov v1 proposals)
+	Proposer string `json:"proposer"`
+	// expedited defines if the proposal is expedited (gov v1 proposals)
+	Expedited bool `json:"expedited"`
 	// status defines the current status of the proposal.
 	Status string `json:"status"`
 	// finalVote defined the result of the proposal
@@ -274,11 +642,40 @@ type Proposal struct {
 	VotingStartTime time.Time `json:"voting_start_time"`
 	// votingEndTime defines the time when the proposal voting period will end
 	VotingEndTime time.Time `json:"voting_end_time"`
+	// tallyStatus defines the vote percentages and whether quorum and thresholds are met
+	TallyStatus TallyStatus `json:"tally_status"`
+	// timeRemaining is the number of seconds until the current deposit or voting period ends
+	TimeRemaining int64 `json:"time_remaining"`
+	// depositProgress is the total deposit as a percentage of the min deposit
+	DepositProgress string `json:"deposit_progress"`
+}
+
+// TallyStatus holds tally percentages and quorum/threshold status of a proposal.
+// all values are percentages.
+type TallyStatus struct {
+	YesPercent        string `json:"yes_percent"`
+	NoPercent         string `json:"no_percent"`
+	AbstainPercent    string `json:"abstain_percent"`
+	NoWithVetoPercent string `json:"no_with_veto_percent"`
+	// turnout is the share of bonded tokens that have voted, only set in voting period
+	Turnout       string `json:"turnout,omitempty"`
+	Quorum        string `json:"quorum"`
+	Threshold     string `json:"threshold"`
+	VetoThreshold string `json:"veto_threshold"`
+	// quorumReached is true if turnout is at least the quorum, only set in voting period
+	QuorumReached *bool `json:"quorum_reached,omitempty"`
+	// thresholdReached is true if yes votes exceed the threshold of non-abstaining votes
+	ThresholdReached bool `json:"threshold_reached"`
+	// vetoed is true if no with veto votes exceed the veto threshold
+	Vetoed bool `json:"vetoed"`
+	// projectedOutcome is the outcome if voting ended with the current tally, only set in voting period
+	ProjectedOutcome string `json:"projected_outcome,omitempty"`
 }
 
 // get all proposals from gov shuttle
 // will return full response string and mapping of proposal id to response string
-func GetAllProposals(ctx context.Context, queryClient gov.QueryClient) ([]Proposal, map[string]string, error) {
+// gov params and bonded tokens are needed to compute tally status and deposit progress
+func GetAllProposals(ctx context.Context, queryClient gov.QueryClient, govParams GovParams, bondedTokens sdk.Int) ([]Proposal, map[string]string, error) {
 	resp, err := queryClient.Proposals(ctx, &gov.QueryProposalsRequest{
 		Pagination: &query.PageRequest{
 			Limit: 1000,
@@ -288,7 +685,7 @@ func GetAllProposals(ctx context.Context, queryClient gov.QueryClient) ([]Propos
 		return nil, nil, err
 	}
 	allProposals := new([]Proposal)
-	proposalMap := make(map[string]string)
+	now := time.Now()
 	for _, proposal := range resp.GetProposals() {
 		// deal with votes
 		var votes gov.TallyResult
@@ -320,6 +717,7 @@ func GetAllProposals(ctx context.Context, queryClient gov.QueryClient) ([]Propos
 			TypeUrl:         proposal.Content.TypeUrl,
 			Title:           title,
 			Description:     description,
+			Content:         GetProposalContent(proposal.Content),
 			Status:          proposal.Status.String(),
 			FinalVote:       votes,
 			SubmitTime:      proposal.SubmitTime,
@@ -327,11 +725,199 @@ func GetAllProposals(ctx context.Context, queryClient gov.QueryClient) ([]Propos
 			TotalDeposit:    proposal.TotalDeposit,
 			VotingStartTime: proposal.VotingStartTime,
 			VotingEndTime:   proposal.VotingEndTime,
+			TallyStatus:     CalculateTallyStatus(votes, govParams.tallyParams, bondedTokens, proposal.Status == gov.StatusVotingPeriod),
 		}
+		SetProposalProgress(&proposalResponse, govParams.minDeposit, now)
 		*allProposals = append(*allProposals, proposalResponse)
-		proposalMap[strconv.Itoa(int(proposal.ProposalId))] = GeneralResultToString(proposalResponse)
 	}
-	return *allProposals, proposalMap, nil
+	return *allProposals, ProposalsToMap(*allProposals), nil
+}
+
+// GovParams holds the deposit, voting and tallying params of the gov module
+type GovParams struct {
+	// minDeposit is the minimum deposit for a proposal to enter voting period
+	MinDeposit []Balance `json:"min_deposit"`
+	// maxDepositPeriod is the maximum period in seconds for holders to deposit on a proposal
+	MaxDepositPeriod int64 `json:"max_deposit_period"`
+	// votingPeriod is the length of the voting period in seconds
+	VotingPeriod int64 `json:"voting_period"`
+	// quorum is the minimum percentage of bonded tokens that must vote
+	Quorum string `json:"quorum"`
+	// threshold is the minimum percentage of non-abstaining yes votes for a proposal to pass
+	Threshold string `json:"threshold"`
+	// vetoThreshold is the percentage of no with veto votes for a proposal to be vetoed
+	VetoThreshold string `json:"veto_threshold"`
+
+	minDeposit  sdk.Coins
+	tallyParams gov.TallyParams
+}
+
+// get the deposit, voting and tallying params of the gov module
+// quorum and thresholds are in percent, the same unit as the tally status of proposals
+func GetGovParams(ctx context.Context, queryClient gov.QueryClient, metadata DenomMetadataMap) (GovParams, error) {
+	depositResp, err := queryClient.Params(ctx, &gov.QueryParamsRequest{
+		ParamsType: gov.ParamDeposit,
+	})
+	if err != nil {
+		return GovParams{}, err
+	}
+	votingResp, err := queryClient.Params(ctx, &gov.QueryParamsRequest{
+		ParamsType: gov.ParamVoting,
+	})
+	if err != nil {
+		return GovParams{}, err
+	}
+	tallyResp, err := queryClient.Params(ctx, &gov.QueryParamsRequest{
+		ParamsType: gov.ParamTallying,
+	})
+	if err != nil {
+		return GovParams{}, err
+	}
+	return GovParams{
+		MinDeposit:       NewBalancesFromCoins(depositResp.DepositParams.MinDeposit, metadata),
+		MaxDepositPeriod: int64(depositResp.DepositParams.MaxDepositPeriod.Seconds()),
+		VotingPeriod:     int64(votingResp.VotingParams.VotingPeriod.Seconds()),
+		Quorum:           tallyResp.TallyParams.Quorum.MulInt64(100).String(),
+		Threshold:        tallyResp.TallyParams.Threshold.MulInt64(100).String(),
+		VetoThreshold:    tallyResp.TallyParams.VetoThreshold.MulInt64(100).String(),
+		minDeposit:       depositResp.DepositParams.MinDeposit,
+		tallyParams:      tallyResp.TallyParams,
+	}, nil
+}
+
+// get the gov params and the currently bonded tokens
+func getGovParamsAndBondedTokens(ctx context.Context, queryClient gov.QueryClient, stakingQueryClient staking.QueryClient, metadata DenomMetadataMap) (GovParams, sdk.Int, error) {
+	govParams, err := GetGovParams(ctx, queryClient, metadata)
+	if err != nil {
+		return GovParams{}, sdk.Int{}, err
+	}
+	poolResp, err := stakingQueryClient.Pool(ctx, &staking.QueryPoolRequest{})
+	if err != nil {
+		return GovParams{}, sdk.Int{}, err
+	}
+	return govParams, poolResp.Pool.BondedTokens, nil
+}
+
+// map proposal id to response string
+func ProposalsToMap(proposals []Proposal) map[string]string {
+	proposalMap := make(map[string]string)
+	for _, proposal := range proposals {
+		proposalMap[strconv.Itoa(int(proposal.ProposalId))] = GeneralResultToString(proposal)
+	}
+	return proposalMap
+}
+
+// ProposalVote holds a single vote on a proposal
+type ProposalVote struct {
+	ProposalId uint64 `json:"proposal_id"`
+	Voter      string `json:"voter"`
+	// option is the single vote option, empty for split votes
+	Option string `json:"option"`
+	// options are the weighted vote options
+	Options []WeightedVoteOption `json:"options"`
+}
+
+// WeightedVoteOption holds a vote option and its weight
+type WeightedVoteOption struct {
+	Option string `json:"option"`
+	Weight string `json:"weight"`
+}
+
+// ProposalVotes holds a page of votes and the key to request the next page
+type ProposalVotes struct {
+	Votes   []ProposalVote `json:"votes"`
+	NextKey []byte         `json:"next_key"`
+}
+
+// ProposalDeposit holds a single deposit on a proposal
+type ProposalDeposit struct {
+	ProposalId uint64    `json:"proposal_id"`
+	Depositor  string    `json:"depositor"`
+	Amount     []Balance `json:"amount"`
+}
+
+func newProposalVote(vote gov.Vote) ProposalVote {
+	proposalVote := ProposalVote{
+		ProposalId: vote.ProposalId,
+		Voter:      vote.Voter,
+		Options:    []WeightedVoteOption{},
+	}
+	for _, option := range vote.Options {
+		proposalVote.Options = append(proposalVote.Options, WeightedVoteOption{
+			Option: option.Option.String(),
+			Weight: option.Weight.String(),
+		})
+	}
+	if len(vote.Options) == 1 {
+		proposalVote.Option = vote.Options[0].Option.String()
+	}
+	return proposalVote
+}
+
+// get a page of votes on a proposal, pageKey is the next key returned by the previous page
+func GetProposalVotes(ctx context.Context, queryClient gov.QueryClient, proposalId uint64, pageKey []byte, limit uint64) (ProposalVotes, error) {
+	resp, err := queryClient.Votes(ctx, &gov.QueryVotesRequest{
+		ProposalId: proposalId,
+		Pagination: &query.PageRequest{
+			Key:   pageKey,
+			Limit: limit,
+		},
+	})
+	if err != nil {
+		return ProposalVotes{}, fmt.Errorf("failed to fetch votes: %w", err)
+	}
+	votes := ProposalVotes{
+		Votes: []ProposalVote{},
+	}
+	for _, vote := range resp.GetVotes() {
+		votes.Votes = append(votes.Votes, newProposalVote(vote))
+	}
+	if resp.GetPagination() != nil {
+		votes.NextKey = resp.GetPagination().NextKey
+	}
+	return votes, nil
+}
+
+// get the vote of a single voter on a proposal
+func GetProposalVote(ctx context.Context, queryClient gov.QueryClient, proposalId uint64, voter string) (ProposalVote, error) {
+	resp, err := queryClient.Vote(ctx, &gov.QueryVoteRequest{
+		ProposalId: proposalId,
+		Voter:      voter,
+	})
+	if err != nil {
+		return ProposalVote{}, fmt.Errorf("failed to fetch vote: %w", err)
+	}
+	return newProposalVote(resp.Vote), nil
+}
+
+// get all deposits on a proposal, paging through the full result set
+func GetProposalDeposits(ctx context.Context, queryClient gov.QueryClient, proposalId uint64, metadata DenomMetadataMap) ([]ProposalDeposit, error) {
+	deposits := []ProposalDeposit{}
+	var nextKey []byte
+	for {
+		resp, err := queryClient.Deposits(ctx, &gov.QueryDepositsRequest{
+			ProposalId: proposalId,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch deposits: %w", err)
+		}
+		for _, deposit := range resp.GetDeposits() {
+			deposits = append(deposits, ProposalDeposit{
+				ProposalId: deposit.ProposalId,
+				Depositor:  deposit.Depositor,
+				Amount:     NewBalancesFromCoins(deposit.Amount, metadata),
+			})
+		}
+		if resp.GetPagination() == nil || len(resp.GetPagination().NextKey) == 0 {
+			break
+		}
+		nextKey = resp.GetPagination().NextKey
+	}
+	return deposits, nil
 }
 
 // CSR
@@ -346,26 +932,592 @@ type CSR struct {
 	Revenue string `json:"revenue"`
 }
 
-// get all CSRS
+// get all CSRS, paging through the full result set
 // will return full response string and mapping of nft id to response string
 func GetCSRS(ctx context.Context, queryClient csr.QueryClient) ([]CSR, map[string]string, error) {
-	resp, err := queryClient.CSRs(ctx, &csr.QueryCSRsRequest{Pagination: &query.PageRequest{
-		Limit: 1000,
-	}})
+	allCsrs := []CSR{}
+	csrMap := make(map[string]string)
+	var nextKey []byte
+	for {
+		resp, err := queryClient.CSRs(ctx, &csr.QueryCSRsRequest{Pagination: &query.PageRequest{
+			Key:   nextKey,
+			Limit: 1000,
+		}})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, csr := range resp.GetCsrs() {
+			csrResponse := newCSR(csr)
+			allCsrs = append(allCsrs, csrResponse)
+			csrMap[strconv.Itoa(int(csr.GetId()))] = GeneralResultToString(csrResponse)
+		}
+		if resp.GetPagination() == nil || len(resp.GetPagination().NextKey) == 0 {
+			break
+		}
+		nextKey = resp.GetPagination().NextKey
+	}
+	return allCsrs, csrMap, nil
+}
+
+func newCSR(csr csr.CSR) CSR {
+	return CSR{
+		Id:        csr.GetId(),
+		Contracts: csr.GetContracts(),
+		Txs:       csr.GetTxs(),
+		Revenue:   csr.Revenue.String(),
+	}
+}
+
+// get the CSR an EVM contract is registered to
+func GetCSRByContract(ctx context.Context, queryClient csr.QueryClient, contractAddress string) (CSR, error) {
+	resp, err := queryClient.CSRByContract(ctx, &csr.QueryCSRByContractRequest{
+		Address: contractAddress,
+	})
 	if err != nil {
-		return nil, nil, err
+		return CSR{}, fmt.Errorf("failed to fetch csr by contract: %w", err)
 	}
-	allCsrs := new([]CSR)
-	csrMap := make(map[string]string)
-	for _, csr := range resp.GetCsrs() {
-		csrResponse := CSR{
-			Id:        csr.GetId(),
-			Contracts: csr.GetContracts(),
-			Txs:       csr.GetTxs(),
-			Revenue:   csr.Revenue.String(),
+	return newCSR(resp.Csr), nil
+}
+
+// CSRParams holds the params of the CSR module
+type CSRParams struct {
+	// enableCsr defines whether CSR is enabled
+	EnableCsr bool `json:"enable_csr"`
+	// csrShares is the share of transaction fees distributed to CSR NFTs
+	CsrShares string `json:"csr_shares"`
+}
+
+// get the params of the CSR module
+func GetCSRParams(ctx context.Context, queryClient csr.QueryClient) (CSRParams, error) {
+	resp, err := queryClient.Params(ctx, &csr.QueryParamsRequest{})
+	if err != nil {
+		return CSRParams{}, err
+	}
+	return CSRParams{
+		EnableCsr: resp.Params.EnableCsr,
+		CsrShares: resp.Params.CsrShares.String(),
+	}, nil
+}
+
+// get the address of the turnstile contract
+func GetTurnstileAddress(ctx context.Context, queryClient csr.QueryClient) (string, error) {
+	resp, err := queryClient.Turnstile(ctx, &csr.QueryTurnstileRequest{})
+	if err != nil {
+		return "", err
+	}
+	return resp.Address, nil
+}
+
+// CSRRevenueDelta holds the revenue earned by a CSR NFT since the previous tick
+type CSRRevenueDelta struct {
+	Id uint64 `json:"id"`
+	// time the revenue change was observed
+	Time time.Time `json:"time"`
+	// revenue is the cumulative revenue of the NFT
+	Revenue string `json:"revenue"`
+	// delta is the revenue earned since the previous observation
+	Delta string `json:"delta"`
+}
+
+// CalculateCSRRevenueDeltas compares the revenue of each CSR with its revenue at the previous tick
+// and returns the deltas of NFTs that earned revenue, along with the current revenue of every NFT.
+// NFTs seen for the first time have no previous revenue and only set the baseline.
+func CalculateCSRRevenueDeltas(previousRevenue map[uint64]sdk.Int, csrs []CSR, now time.Time) ([]CSRRevenueDelta, map[uint64]sdk.Int) {
+	deltas := []CSRRevenueDelta{}
+	currentRevenue := make(map[uint64]sdk.Int)
+	for _, csr := range csrs {
+		revenue, ok := sdk.NewIntFromString(csr.Revenue)
+		if !ok {
+			continue
+		}
+		currentRevenue[csr.Id] = revenue
+		previous, seen := previousRevenue[csr.Id]
+		if !seen || !revenue.GT(previous) {
+			continue
+		}
+		deltas = append(deltas, CSRRevenueDelta{
+			Id:      csr.Id,
+			Time:    now,
+			Revenue: revenue.String(),
+			Delta:   revenue.Sub(previous).String(),
+		})
+	}
+	return deltas, currentRevenue
+}
+
+// DISTRIBUTION
+
+// get the community pool balance in base and display units
+func GetCommunityPool(ctx context.Context, queryClient distrtypes.QueryClient, metadata DenomMetadataMap) ([]Balance, error) {
+	resp, err := queryClient.CommunityPool(ctx, &distrtypes.QueryCommunityPoolRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch community pool: %w", err)
+	}
+	return NewBalancesFromDecCoins(resp.Pool, metadata), nil
+}
+
+// get the accumulated commission of a validator in base and display units
+func GetValidatorCommission(ctx context.Context, queryClient distrtypes.QueryClient, validatorAddress string, metadata DenomMetadataMap) ([]Balance, error) {
+	resp, err := queryClient.ValidatorCommission(ctx, &distrtypes.QueryValidatorCommissionRequest{
+		ValidatorAddress: validatorAddress,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch validator commission: %w", err)
+	}
+	return NewBalancesFromDecCoins(resp.Commission.Commission, metadata), nil
+}
+
+// get the outstanding (not yet withdrawn) rewards of a validator in base and display units
+func GetValidatorOutstandingRewards(ctx context.Context, queryClient distrtypes.QueryClient, validatorAddress string, metadata DenomMetadataMap) ([]Balance, error) {
+	resp, err := queryClient.ValidatorOutstandingRewards(ctx, &distrtypes.QueryValidatorOutstandingRewardsRequest{
+		ValidatorAddress: validatorAddress,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch validator outstanding rewards: %w", err)
+	}
+	return NewBalancesFromDecCoins(resp.Rewards.Rewards, metadata), nil
+}
+
+// CHAIN
+
+// ChainStatus holds a chain level overview computed each tick
+type ChainStatus struct {
+	ChainId      string    `json:"chain_id"`
+	LatestHeight int64     `json:"latest_height"`
+	LatestTime   time.Time `json:"latest_time"`
+	// averageBlockTime is the average block time in seconds measured over the latest blocks
+	AverageBlockTime float64 `json:"average_block_time"`
+	// inflation is the current annual inflation rate
+	Inflation        string `json:"inflation"`
+	AnnualProvisions string `json:"annual_provisions"`
+	// bondDenom is the denom of staking tokens, totalSupply is the supply of this denom
+	BondDenom       string    `json:"bond_denom"`
+	TotalSupply     string    `json:"total_supply"`
+	BondedTokens    string    `json:"bonded_tokens"`
+	NotBondedTokens string    `json:"not_bonded_tokens"`
+	BondedRatio     string    `json:"bonded_ratio"`
+	CommunityPool   []Balance `json:"community_pool"`
+	// activeValidators is the number of validators in the active (bonded) set
+	ActiveValidators int `json:"active_validators"`
+}
+
+// CalculateAverageBlockTime returns the average time in seconds between two blocks
+func CalculateAverageBlockTime(pastHeight int64, pastTime time.Time, latestHeight int64, latestTime time.Time) float64 {
+	if latestHeight <= pastHeight {
+		return 0
+	}
+	return latestTime.Sub(pastTime).Seconds() / float64(latestHeight-pastHeight)
+}
+
+// get the average block time in seconds over the latest blockTimeWindow blocks, along with the latest header
+func GetAverageBlockTime(ctx context.Context, tmQueryClient tmservice.ServiceClient, blockTimeWindow int64) (float64, tmproto.Header, error) {
+	latestResp, err := tmQueryClient.GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		return 0, tmproto.Header{}, err
+	}
+	latest := latestResp.Block.Header
+	pastHeight := latest.Height - blockTimeWindow
+	if pastHeight < 1 {
+		pastHeight = 1
+	}
+	pastResp, err := tmQueryClient.GetBlockByHeight(ctx, &tmservice.GetBlockByHeightRequest{
+		Height: pastHeight,
+	})
+	if err != nil {
+		return 0, tmproto.Header{}, err
+	}
+	past := pastResp.Block.Header
+	return CalculateAverageBlockTime(past.Height, past.Time, latest.Height, latest.Time), latest, nil
+}
+
+// active validators are the validators in the bonded set
+func countActiveValidators(validators []Validator) int {
+	count := 0
+	for _, validator := range validators {
+		if validator.Status == staking.Bonded.String() {
+			count++
+		}
+	}
+	return count
+}
+
+// get a chain level overview of blocks, inflation, supply, staking and the community pool
+func GetChainStatus(ctx context.Context, tmQueryClient tmservice.ServiceClient, inflationQueryClient inflation.QueryClient, bankQueryClient bank.QueryClient, stakingQueryClient staking.QueryClient, distributionQueryClient distrtypes.QueryClient, validators []Validator, metadata DenomMetadataMap) (ChainStatus, error) {
+	averageBlockTime, latest, err := GetAverageBlockTime(ctx, tmQueryClient, BlockTimeWindow)
+	if err != nil {
+		return ChainStatus{}, fmt.Errorf("failed to fetch blocks: %w", err)
+	}
+	inflationResp, err := inflationQueryClient.Inflation(ctx, &inflation.QueryInflationRequest{})
+	if err != nil {
+		return ChainStatus{}, fmt.Errorf("failed to fetch inflation: %w", err)
+	}
+	provisionsResp, err := inflationQueryClient.AnnualProvisions(ctx, &inflation.QueryAnnualProvisionsRequest{})
+	if err != nil {
+		return ChainStatus{}, fmt.Errorf("failed to fetch annual provisions: %w", err)
+	}
+	bondDenom, err := GetBondDenom(ctx, stakingQueryClient)
+	if err != nil {
+		return ChainStatus{}, err
+	}
+	supplyResp, err := bankQueryClient.SupplyOf(ctx, &bank.QuerySupplyOfRequest{
+		Denom: bondDenom,
+	})
+	if err != nil {
+		return ChainStatus{}, fmt.Errorf("failed to fetch total supply: %w", err)
+	}
+	poolResp, err := stakingQueryClient.Pool(ctx, &staking.QueryPoolRequest{})
+	if err != nil {
+		return ChainStatus{}, fmt.Errorf("failed to fetch staking pool: %w", err)
+	}
+	communityPoolResp, err := distributionQueryClient.CommunityPool(ctx, &distrtypes.QueryCommunityPoolRequest{})
+	if err != nil {
+		return ChainStatus{}, fmt.Errorf("failed to fetch community pool: %w", err)
+	}
+
+	bondedRatio := sdk.ZeroDec()
+	if supplyResp.Amount.Amount.IsPositive() {
+		bondedRatio = sdk.NewDecFromInt(poolResp.Pool.BondedTokens).QuoInt(supplyResp.Amount.Amount)
+	}
+	return ChainStatus{
+		ChainId:          latest.ChainID,
+		LatestHeight:     latest.Height,
+		LatestTime:       latest.Time,
+		AverageBlockTime: averageBlockTime,
+		Inflation:        inflationResp.Inflation.String(),
+		AnnualProvisions: provisionsResp.AnnualProvisions.String(),
+		BondDenom:        bondDenom,
+		TotalSupply:      supplyResp.Amount.Amount.String(),
+		BondedTokens:     poolResp.Pool.BondedTokens.String(),
+		NotBondedTokens:  poolResp.Pool.NotBondedTokens.String(),
+		BondedRatio:      bondedRatio.String(),
+		CommunityPool:    NewBalancesFromDecCoins(communityPoolResp.Pool, metadata),
+		ActiveValidators: countActiveValidators(validators),
+	}, nil
+}
+
+// BANK
+
+// get metadata of all denoms registered in the bank module, keyed by base denom
+func GetDenomsMetadata(ctx context.Context, queryClient bank.QueryClient) (DenomMetadataMap, error) {
+	metadata := make(DenomMetadataMap)
+	var nextKey []byte
+	for {
+		resp, err := queryClient.DenomsMetadata(ctx, &bank.QueryDenomsMetadataRequest{
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, err
 		}
-		*allCsrs = append(*allCsrs, csrResponse)
-		csrMap[strconv.Itoa(int(csr.GetId()))] = GeneralResultToString(csrResponse)
+		for _, meta := range resp.GetMetadatas() {
+			metadata[meta.Base] = meta
+		}
+		if resp.GetPagination() == nil || len(resp.GetPagination().NextKey) == 0 {
+			break
+		}
+		nextKey = resp.GetPagination().NextKey
+	}
+	return metadata, nil
+}
+
+// TokenBalance is a bank balance along with the balance of its ERC20 representation
+type TokenBalance struct {
+	Balance
+	// erc20Address is the address of the ERC20 token paired with the denom, if any
+	Erc20Address string `json:"erc20_address,omitempty"`
+	// erc20Amount is the ERC20 token balance of the account's EVM address in base units
+	Erc20Amount string `json:"erc20_amount,omitempty"`
+}
+
+// AccountBalances holds the native and EVM balances of an account
+type AccountBalances struct {
+	Address    string         `json:"address"`
+	EvmAddress string         `json:"evm_address"`
+	Balances   []TokenBalance `json:"balances"`
+}
+
+// Denom is the metadata of a bank denom and its ERC20 representation
+type Denom struct {
+	Base         string `json:"base"`
+	Display      string `json:"display"`
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	Description  string `json:"description"`
+	Decimals     uint32 `json:"decimals"`
+	Erc20Address string `json:"erc20_address,omitempty"`
+}
+
+// get the ERC20 balances of an EVM address for every enabled token pair in one multicall, keyed by denom
+func GetErc20Balances(ctx context.Context, tokenPairs []erc20.TokenPair, account common.Address) (map[string]*big.Int, error) {
+	balances := make(map[string]*big.Int)
+	viewcalls := multicall.ViewCalls{}
+	for _, pair := range tokenPairs {
+		if !pair.Enabled {
+			continue
+		}
+		viewcalls = append(viewcalls, multicall.NewViewCall(pair.Denom, pair.Erc20Address, "balanceOf(address)(uint256)", []interface{}{account.Hex()}))
+	}
+	if len(viewcalls) == 0 {
+		return balances, nil
+	}
+	calldata, err := viewcalls.GetCallData()
+	if err != nil {
+		return nil, err
+	}
+	mc, err := multicall.NewMulticall(config.MulticallAddress, config.EthClient)
+	if err != nil {
+		return nil, err
+	}
+	res, err := mc.Aggregate(&bind.CallOpts{Context: ctx}, calldata)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := viewcalls.Decode(res)
+	if err != nil {
+		return nil, err
+	}
+	for denom, values := range ret.Calls {
+		if len(values) != 1 {
+			return nil, fmt.Errorf("no erc20 balance for %s", denom)
+		}
+		value, _ := values[0].(string)
+		balance, ok := new(big.Int).SetString(value, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid erc20 balance for %s: %v", denom, values[0])
+		}
+		balances[denom] = balance
+	}
+	return balances, nil
+}
+
+// get all bank balances of an address, with each denom's ERC20 pair and the ERC20 balance of the
+// address's EVM form, so native and EVM balances can be shown together
+// amounts are converted to display units with the given denom metadata
+func FetchAccountBalances(ctx context.Context, bankQueryClient bank.QueryClient, erc20QueryClient erc20.QueryClient, address string, metadata DenomMetadataMap) (*AccountBalances, error) {
+	_, addressBytes, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bech32 address: %w", err)
+	}
+	evmAddress := common.BytesToAddress(addressBytes)
+
+	coins := sdk.Coins{}
+	var nextKey []byte
+	for {
+		resp, err := bankQueryClient.AllBalances(ctx, &bank.QueryAllBalancesRequest{
+			Address: address,
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch balances: %w", err)
+		}
+		coins = append(coins, resp.Balances...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+	tokenPairs, err := GetTokenPairs(ctx, erc20QueryClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token pairs: %w", err)
+	}
+
+	erc20Balances, err := GetErc20Balances(ctx, tokenPairs, evmAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch erc20 balances: %w", err)
+	}
+	return &AccountBalances{
+		Address:    address,
+		EvmAddress: evmAddress.Hex(),
+		Balances:   MergeTokenBalances(coins, TokenPairsByDenom(tokenPairs), erc20Balances, metadata),
+	}, nil
+}
+
+// MergeTokenBalances joins bank balances with their ERC20 pairs and ERC20 balances.
+// Denoms only held as ERC20 tokens are included with a zero bank balance. The result is sorted by denom.
+func MergeTokenBalances(coins sdk.Coins, tokenPairs map[string]erc20.TokenPair, erc20Balances map[string]*big.Int, metadata DenomMetadataMap) []TokenBalance {
+	balances := make(map[string]TokenBalance)
+	for _, coin := range coins {
+		balances[coin.Denom] = TokenBalance{Balance: NewBalanceFromCoin(coin, metadata)}
+	}
+	for denom, erc20Balance := range erc20Balances {
+		balance, ok := balances[denom]
+		if !ok {
+			if erc20Balance.Sign() == 0 {
+				continue
+			}
+			balance = TokenBalance{Balance: NewBalanceFromCoin(sdk.NewCoin(denom, sdk.ZeroInt()), metadata)}
+		}
+		balance.Erc20Amount = erc20Balance.String()
+		balances[denom] = balance
+	}
+	tokenBalances := make([]TokenBalance, 0, len(balances))
+	for denom, balance := range balances {
+		if pair, ok := tokenPairs[denom]; ok {
+			balance.Erc20Address = pair.Erc20Address
+		}
+		tokenBalances = append(tokenBalances, balance)
+	}
+	sort.Slice(tokenBalances, func(i, j int) bool {
+		return tokenBalances[i].Denom < tokenBalances[j].Denom
+	})
+	return tokenBalances
+}
+
+// get the total supply of all denoms, with each denom's ERC20 pair
+func GetTotalSupply(ctx context.Context, bankQueryClient bank.QueryClient, metadata DenomMetadataMap, tokenPairs map[string]erc20.TokenPair) ([]TokenBalance, error) {
+	supply := []TokenBalance{}
+	var nextKey []byte
+	for {
+		resp, err := bankQueryClient.TotalSupply(ctx, &bank.QueryTotalSupplyRequest{
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, coin := range resp.Supply {
+			balance := TokenBalance{Balance: NewBalanceFromCoin(coin, metadata)}
+			if pair, ok := tokenPairs[coin.Denom]; ok {
+				balance.Erc20Address = pair.Erc20Address
+			}
+			supply = append(supply, balance)
+		}
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+	return supply, nil
+}
+
+// GetDenoms returns the metadata of all denoms with their ERC20 pairs, sorted by base denom
+func GetDenoms(metadata DenomMetadataMap, tokenPairs map[string]erc20.TokenPair) []Denom {
+	denoms := make([]Denom, 0, len(metadata))
+	for base, meta := range metadata {
+		denom := Denom{
+			Base:        base,
+			Display:     meta.Display,
+			Name:        meta.Name,
+			Symbol:      meta.Symbol,
+			Description: meta.Description,
+		}
+		for _, unit := range meta.DenomUnits {
+			if unit.Denom == meta.Display {
+				denom.Decimals = unit.Exponent
+			}
+		}
+		if pair, ok := tokenPairs[base]; ok {
+			denom.Erc20Address = pair.Erc20Address
+		}
+		denoms = append(denoms, denom)
+	}
+	sort.Slice(denoms, func(i, j int) bool {
+		return denoms[i].Base < denoms[j].Base
+	})
+	return denoms
+}
+
+// ERC20
+
+// get all token pairs registered in the erc20 module
+func GetTokenPairs(ctx context.Context, queryClient erc20.QueryClient) ([]erc20.TokenPair, error) {
+	tokenPairs := []erc20.TokenPair{}
+	var nextKey []byte
+	for {
+		resp, err := queryClient.TokenPairs(ctx, &erc20.QueryTokenPairsRequest{
+			Pagination: &query.PageRequest{
+				Key:   nextKey,
+				Limit: 1000,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		tokenPairs = append(tokenPairs, resp.TokenPairs...)
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+	return tokenPairs, nil
+}
+
+// TokenPairsByDenom maps token pairs by their cosmos denom
+func TokenPairsByDenom(tokenPairs []erc20.TokenPair) map[string]erc20.TokenPair {
+	pairs := make(map[string]erc20.TokenPair)
+	for _, pair := range tokenPairs {
+		pairs[pair.Denom] = pair
+	}
+	return pairs
+}
+
+// TokenPairInfo is an erc20 module token pair joined with our token list metadata
+type TokenPairInfo struct {
+	Denom        string `json:"denom"`
+	Erc20Address string `json:"erc20_address"`
+	Enabled      bool   `json:"enabled"`
+	// contractOwner is the owner of the ERC20 contract (OWNER_MODULE or OWNER_EXTERNAL)
+	ContractOwner string `json:"contract_owner"`
+	// name, symbol, decimals and logoURI are taken from the token list, if the token is listed
+	Name     string `json:"name,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals int64  `json:"decimals,omitempty"`
+	LogoURI  string `json:"logoURI,omitempty"`
+}
+
+// TokenPairsPage holds a page of token pairs, pass nextKey to get the next page
+type TokenPairsPage struct {
+	TokenPairs []TokenPairInfo `json:"token_pairs"`
+	NextKey    []byte          `json:"next_key"`
+}
+
+// NewTokenPairInfo joins a token pair with the token list entry of its ERC20 address
+func NewTokenPairInfo(pair erc20.TokenPair, tokens []config.Token) TokenPairInfo {
+	info := TokenPairInfo{
+		Denom:         pair.Denom,
+		Erc20Address:  pair.Erc20Address,
+		Enabled:       pair.Enabled,
+		ContractOwner: pair.ContractOwner.String(),
+	}
+	for _, token := range tokens {
+		// addresses in the token list are not always checksummed
+		if strings.EqualFold(token.Address, pair.Erc20Address) {
+			info.Name = token.Name
+			info.Symbol = token.Symbol
+			info.Decimals = token.Decimals
+			info.LogoURI = token.LogoURI
+			break
+		}
+	}
+	return info
+}
+
+// get a page of token pairs with token list metadata, pageKey is the next key returned by the previous page
+func GetTokenPairsPage(ctx context.Context, queryClient erc20.QueryClient, pageKey []byte, limit uint64, tokens []config.Token) (TokenPairsPage, error) {
+	resp, err := queryClient.TokenPairs(ctx, &erc20.QueryTokenPairsRequest{
+		Pagination: &query.PageRequest{
+			Key:   pageKey,
+			Limit: limit,
+		},
+	})
+	if err != nil {
+		return TokenPairsPage{}, fmt.Errorf("failed to fetch token pairs: %w", err)
+	}
+	page := TokenPairsPage{
+		TokenPairs: []TokenPairInfo{},
+	}
+	for _, pair := range resp.GetTokenPairs() {
+		page.TokenPairs = append(page.TokenPairs, NewTokenPairInfo(pair, tokens))
+	}
+	if resp.GetPagination() != nil {
+		page.NextKey = resp.GetPagination().NextKey
 	}
-	return *allCsrs, csrMap, nil
+	return page, nil
 }
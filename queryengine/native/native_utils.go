@@ -2,29 +2,223 @@ package queryengine
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
+	cantoConfig "github.com/Canto-Network/Canto/v6/cmd/config"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
 	inflation "github.com/cosmos/cosmos-sdk/x/mint/types"
 	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/ethereum/go-ethereum/common"
 )
 
-// CalculateStakingAPR calculates the APR based on mint provision and total bonded tokens
-func CalculateStakingAPR(pool *staking.QueryPoolResponse, mintProvision *inflation.QueryAnnualProvisionsResponse) sdk.Dec {
-    // Convert bondedTokens to sdk.Dec
-    bondedTokensDec := sdk.NewDecFromInt(pool.Pool.BondedTokens)
+// CalculateStakingAPR calculates the network staking APR as a percentage,
+// net of the community tax taken from inflation before it reaches stakers:
+// (annualProvisions * (1 - communityTax) / bondedTokens) * 100
+func CalculateStakingAPR(pool *staking.QueryPoolResponse, mintProvision *inflation.QueryAnnualProvisionsResponse, communityTax sdk.Dec) sdk.Dec {
+	// Convert bondedTokens to sdk.Dec
+	bondedTokensDec := sdk.NewDecFromInt(pool.Pool.BondedTokens)
 
-    // Ensure bondedTokensDec is not zero to avoid division by zero
-    if bondedTokensDec.IsZero() {
-        return sdk.NewDec(0)
-    }
+	// Ensure bondedTokensDec is not zero to avoid division by zero
+	if bondedTokensDec.IsZero() {
+		return sdk.NewDec(0)
+	}
+
+	// portion of provisions distributed to stakers
+	stakerProvisions := mintProvision.AnnualProvisions.Mul(sdk.OneDec().Sub(communityTax))
+
+	return stakerProvisions.Quo(bondedTokensDec).MulInt64(100)
+}
+
+// CalculateValidatorAPR calculates the APR a delegator actually earns with a validator,
+// which is the network staking APR net of the validator's commission rate
+func CalculateValidatorAPR(stakingApr sdk.Dec, commission sdk.Dec) sdk.Dec {
+	return stakingApr.Mul(sdk.OneDec().Sub(commission))
+}
+
+// projected outcomes of a proposal tally
+const (
+	OutcomePassed           = "PASSED"
+	OutcomeRejected         = "REJECTED"
+	OutcomeRejectedVeto     = "REJECTED_VETO"
+	OutcomeRejectedNoQuorum = "REJECTED_NO_QUORUM"
+)
+
+// CalculateTallyStatus computes vote percentages and whether the threshold and veto threshold
+// of the tally params are met. turnout, quorum and the projected outcome are only computed while
+// the proposal is in voting period, since bonded tokens at the end of voting are not known.
+func CalculateTallyStatus(tally gov.TallyResult, params gov.TallyParams, bondedTokens sdk.Int, inVotingPeriod bool) TallyStatus {
+	status := TallyStatus{
+		YesPercent:        sdk.ZeroDec().String(),
+		NoPercent:         sdk.ZeroDec().String(),
+		AbstainPercent:    sdk.ZeroDec().String(),
+		NoWithVetoPercent: sdk.ZeroDec().String(),
+		Quorum:            params.Quorum.MulInt64(100).String(),
+		Threshold:         params.Threshold.MulInt64(100).String(),
+		VetoThreshold:     params.VetoThreshold.MulInt64(100).String(),
+	}
+
+	quorumReached := false
+	totalVoted := tally.Yes.Add(tally.No).Add(tally.Abstain).Add(tally.NoWithVeto)
+	if !totalVoted.IsZero() {
+		totalVotedDec := sdk.NewDecFromInt(totalVoted)
+		percentOf := func(votes sdk.Int) sdk.Dec {
+			return sdk.NewDecFromInt(votes).Quo(totalVotedDec).MulInt64(100)
+		}
+		status.YesPercent = percentOf(tally.Yes).String()
+		status.NoPercent = percentOf(tally.No).String()
+		status.AbstainPercent = percentOf(tally.Abstain).String()
+		status.NoWithVetoPercent = percentOf(tally.NoWithVeto).String()
+
+		if bondedTokens.IsPositive() {
+			quorumReached = totalVotedDec.QuoInt(bondedTokens).GTE(params.Quorum)
+		}
+
+		status.Vetoed = sdk.NewDecFromInt(tally.NoWithVeto).Quo(totalVotedDec).GT(params.VetoThreshold)
+
+		nonAbstaining := totalVoted.Sub(tally.Abstain)
+		if nonAbstaining.IsPositive() {
+			status.ThresholdReached = sdk.NewDecFromInt(tally.Yes).QuoInt(nonAbstaining).GT(params.Threshold)
+		}
+	}
+
+	if !inVotingPeriod {
+		return status
+	}
+	status.Turnout = sdk.ZeroDec().String()
+	if bondedTokens.IsPositive() {
+		status.Turnout = sdk.NewDecFromInt(totalVoted).QuoInt(bondedTokens).MulInt64(100).String()
+	}
+	status.QuorumReached = &quorumReached
+	status.ProjectedOutcome = projectedOutcome(quorumReached, status)
+	return status
+}
+
+// projectedOutcome returns the outcome of a tally, following the order the gov module checks it in
+func projectedOutcome(quorumReached bool, status TallyStatus) string {
+	switch {
+	case !quorumReached:
+		return OutcomeRejectedNoQuorum
+	case status.Vetoed:
+		return OutcomeRejectedVeto
+	case status.ThresholdReached:
+		return OutcomePassed
+	default:
+		return OutcomeRejected
+	}
+}
+
+// SetProposalProgress sets the time remaining in the current period and the deposit progress of a proposal
+func SetProposalProgress(proposal *Proposal, minDeposit sdk.Coins, now time.Time) {
+	var periodEnd time.Time
+	switch proposal.Status {
+	case gov.StatusDepositPeriod.String():
+		periodEnd = proposal.DepositEndTime
+	case gov.StatusVotingPeriod.String():
+		periodEnd = proposal.VotingEndTime
+	}
+	proposal.TimeRemaining = 0
+	if periodEnd.After(now) {
+		proposal.TimeRemaining = int64(periodEnd.Sub(now).Seconds())
+	}
+
+	// deposit progress is measured in the denom of the min deposit
+	proposal.DepositProgress = sdk.ZeroDec().String()
+	if len(minDeposit) > 0 && minDeposit[0].Amount.IsPositive() {
+		deposited := sdk.NewDecFromInt(proposal.TotalDeposit.AmountOf(minDeposit[0].Denom))
+		proposal.DepositProgress = deposited.QuoInt(minDeposit[0].Amount).MulInt64(100).String()
+	}
+}
 
-    // mintProvisionAmount is already of type sdk.Dec
-    mintProvisionAmount := mintProvision.AnnualProvisions
+// DenomMetadataMap maps a base denom to its bank module metadata
+type DenomMetadataMap map[string]bank.Metadata
 
-    // Perform the calculation: (mintProvision / totalStake) * 100
-    apr := mintProvisionAmount.Quo(bondedTokensDec)
+// ToDisplayUnits converts a base denom amount to the display denom declared in its metadata
+// if no metadata is known for the denom, the base denom and amount are returned unchanged
+func ToDisplayUnits(coin sdk.DecCoin, metadata DenomMetadataMap) (string, sdk.Dec) {
+	meta, ok := metadata[coin.Denom]
+	if !ok {
+		return coin.Denom, coin.Amount
+	}
+	for _, unit := range meta.DenomUnits {
+		if unit.Denom == meta.Display {
+			scale := sdk.NewDecFromInt(sdk.NewIntWithDecimal(1, int(unit.Exponent)))
+			return meta.Display, coin.Amount.Quo(scale)
+		}
+	}
+	return coin.Denom, coin.Amount
+}
+
+// DenomsToMetadata rebuilds the metadata needed for display units from the denoms the native engine caches
+func DenomsToMetadata(denoms []Denom) DenomMetadataMap {
+	metadata := make(DenomMetadataMap)
+	for _, denom := range denoms {
+		metadata[denom.Base] = bank.Metadata{
+			Base:    denom.Base,
+			Display: denom.Display,
+			DenomUnits: []*bank.DenomUnit{
+				{Denom: denom.Base, Exponent: 0},
+				{Denom: denom.Display, Exponent: denom.Decimals},
+			},
+			Name:        denom.Name,
+			Symbol:      denom.Symbol,
+			Description: denom.Description,
+		}
+	}
+	return metadata
+}
+
+// NewBalanceFromDecCoin returns a Balance with the amount in both base and display units
+func NewBalanceFromDecCoin(coin sdk.DecCoin, metadata DenomMetadataMap) Balance {
+	displayDenom, displayAmount := ToDisplayUnits(coin, metadata)
+	return Balance{
+		Denom:         coin.Denom,
+		Amount:        coin.Amount.String(),
+		DisplayDenom:  displayDenom,
+		DisplayAmount: displayAmount.String(),
+	}
+}
+
+// NewBalancesFromDecCoins returns a Balance for every coin, with amounts in both base and display units
+func NewBalancesFromDecCoins(coins sdk.DecCoins, metadata DenomMetadataMap) []Balance {
+	balances := []Balance{}
+	for _, coin := range coins {
+		balances = append(balances, NewBalanceFromDecCoin(coin, metadata))
+	}
+	return balances
+}
+
+// NewBalanceFromCoin returns a Balance with the integer base amount and its display units
+func NewBalanceFromCoin(coin sdk.Coin, metadata DenomMetadataMap) Balance {
+	balance := NewBalanceFromDecCoin(sdk.NewDecCoinFromCoin(coin), metadata)
+	balance.Amount = coin.Amount.String()
+	return balance
+}
 
-    return apr
+// NewBalancesFromCoins returns a Balance for every coin, with integer base amounts and their display units
+func NewBalancesFromCoins(coins sdk.Coins, metadata DenomMetadataMap) []Balance {
+	balances := []Balance{}
+	for _, coin := range coins {
+		balances = append(balances, NewBalanceFromCoin(coin, metadata))
+	}
+	return balances
+}
+
+// amountGreaterThan compares two integer amount strings, treating unparsable amounts as zero
+func amountGreaterThan(a string, b string) bool {
+	amountA, ok := sdk.NewIntFromString(a)
+	if !ok {
+		amountA = sdk.ZeroInt()
+	}
+	amountB, ok := sdk.NewIntFromString(b)
+	if !ok {
+		amountB = sdk.ZeroInt()
+	}
+	return amountA.GT(amountB)
 }
 
 func GeneralResultToString(results interface{}) string {
@@ -34,3 +228,55 @@ func GeneralResultToString(results interface{}) string {
 	}
 	return string(ret)
 }
+
+// Addresses holds every form of a 20 byte address
+type Addresses struct {
+	// account is the bech32 account address
+	Account string `json:"account"`
+	// valoper is the bech32 validator operator address
+	Valoper string `json:"valoper"`
+	// evm is the checksummed 0x address
+	Evm string `json:"evm"`
+}
+
+// ConvertAddress converts a bech32 account, bech32 validator operator or 0x address to all address forms.
+// Mixed case 0x addresses must have a valid checksum, all lower or upper case 0x addresses are not checksummed.
+func ConvertAddress(address string) (Addresses, error) {
+	var addressBytes []byte
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		if !common.IsHexAddress(address) {
+			return Addresses{}, fmt.Errorf("invalid hex address: %s", address)
+		}
+		hexAddress := common.HexToAddress(address)
+		digits := address[2:]
+		if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && hexAddress.Hex()[2:] != digits {
+			return Addresses{}, fmt.Errorf("invalid checksum for address: %s", address)
+		}
+		addressBytes = hexAddress.Bytes()
+	} else {
+		prefix, decoded, err := bech32.DecodeAndConvert(address)
+		if err != nil {
+			return Addresses{}, fmt.Errorf("invalid bech32 address: %s", address)
+		}
+		if prefix != cantoConfig.Bech32PrefixAccAddr && prefix != cantoConfig.Bech32PrefixValAddr {
+			return Addresses{}, fmt.Errorf("invalid bech32 prefix %s for address: %s", prefix, address)
+		}
+		if len(decoded) != common.AddressLength {
+			return Addresses{}, fmt.Errorf("invalid address length for address: %s", address)
+		}
+		addressBytes = decoded
+	}
+	account, err := bech32.ConvertAndEncode(cantoConfig.Bech32PrefixAccAddr, addressBytes)
+	if err != nil {
+		return Addresses{}, err
+	}
+	valoper, err := bech32.ConvertAndEncode(cantoConfig.Bech32PrefixValAddr, addressBytes)
+	if err != nil {
+		return Addresses{}, err
+	}
+	return Addresses{
+		Account: account,
+		Valoper: valoper,
+		Evm:     common.BytesToAddress(addressBytes).Hex(),
+	}, nil
+}
@@ -1,18 +1,29 @@
 package queryengine
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
-	inflation "github.com/cosmos/cosmos-sdk/x/mint/types"
+	"althea-api/config"
+
+	erc20 "github.com/Canto-Network/Canto/v6/x/erc20/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
+	inflation "github.com/cosmos/cosmos-sdk/x/mint/types"
+	slashing "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
 )
 
-func TestGetStakingAPR(t *testing.T) {
+func TestCalculateStakingAPR(t *testing.T) {
 	type args struct {
 		pool          staking.QueryPoolResponse
 		mintProvision inflation.QueryAnnualProvisionsResponse
+		communityTax  sdk.Dec
 	}
 	tests := []struct {
 		name string
@@ -28,8 +39,9 @@ func TestGetStakingAPR(t *testing.T) {
 					},
 				},
 				mintProvision: inflation.QueryAnnualProvisionsResponse{
-					EpochMintProvision: sdk.NewDecCoin("acanto", sdk.NewInt(100)),
+					AnnualProvisions: sdk.NewDec(100),
 				},
+				communityTax: sdk.ZeroDec(),
 			},
 			want: sdk.NewDec(0),
 		},
@@ -42,13 +54,14 @@ func TestGetStakingAPR(t *testing.T) {
 					},
 				},
 				mintProvision: inflation.QueryAnnualProvisionsResponse{
-					EpochMintProvision: sdk.NewDecCoin("acanto", sdk.ZeroInt()),
+					AnnualProvisions: sdk.ZeroDec(),
 				},
+				communityTax: sdk.ZeroDec(),
 			},
 			want: sdk.NewDec(0),
 		},
 		{
-			name: "bonded tokens is less than mint provision",
+			name: "no community tax",
 			args: args{
 				pool: staking.QueryPoolResponse{
 					Pool: staking.Pool{
@@ -56,31 +69,681 @@ func TestGetStakingAPR(t *testing.T) {
 					},
 				},
 				mintProvision: inflation.QueryAnnualProvisionsResponse{
-					AnnualProvisions: types.NewDecWithPrec(100, 0)
+					AnnualProvisions: sdk.NewDec(100),
 				},
+				communityTax: sdk.ZeroDec(),
 			},
-			want: sdk.NewDec(36500000000000),
+			want: sdk.NewDec(100),
 		},
 		{
-			name: "mint provision is less than bonded tokens",
+			name: "community tax is deducted",
 			args: args{
 				pool: staking.QueryPoolResponse{
 					Pool: staking.Pool{
-						BondedTokens: sdk.NewInt(100000000000),
+						BondedTokens: sdk.NewInt(10000),
 					},
 				},
 				mintProvision: inflation.QueryAnnualProvisionsResponse{
-					EpochMintProvision: sdk.NewDecCoin("acanto", sdk.NewInt(100)),
+					AnnualProvisions: sdk.NewDec(1000),
 				},
+				communityTax: sdk.MustNewDecFromStr("0.02"),
+			},
+			want: sdk.MustNewDecFromStr("9.8"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateStakingAPR(&tt.args.pool, &tt.args.mintProvision, tt.args.communityTax); !got.Equal(tt.want) {
+				t.Errorf("CalculateStakingAPR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateValidatorAPR(t *testing.T) {
+	type args struct {
+		stakingApr sdk.Dec
+		commission sdk.Dec
+	}
+	tests := []struct {
+		name string
+		args args
+		want sdk.Dec
+	}{
+		{
+			name: "zero commission",
+			args: args{
+				stakingApr: sdk.NewDec(10),
+				commission: sdk.ZeroDec(),
+			},
+			want: sdk.NewDec(10),
+		},
+		{
+			name: "five percent commission",
+			args: args{
+				stakingApr: sdk.NewDec(10),
+				commission: sdk.MustNewDecFromStr("0.05"),
+			},
+			want: sdk.MustNewDecFromStr("9.5"),
+		},
+		{
+			name: "full commission",
+			args: args{
+				stakingApr: sdk.NewDec(10),
+				commission: sdk.OneDec(),
+			},
+			want: sdk.ZeroDec(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateValidatorAPR(tt.args.stakingApr, tt.args.commission); !got.Equal(tt.want) {
+				t.Errorf("CalculateValidatorAPR() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// testMetadata returns the metadata of the aalthea base denom with an 18 decimal althea display denom
+func testMetadata() DenomMetadataMap {
+	return DenomMetadataMap{
+		"aalthea": bank.Metadata{
+			Base:    "aalthea",
+			Display: "althea",
+			Name:    "Althea",
+			Symbol:  "ALTHEA",
+			DenomUnits: []*bank.DenomUnit{
+				{Denom: "aalthea", Exponent: 0},
+				{Denom: "althea", Exponent: 18},
+			},
+		},
+	}
+}
+
+func TestToDisplayUnits(t *testing.T) {
+	metadata := testMetadata()
+	tests := []struct {
+		name       string
+		coin       sdk.DecCoin
+		wantDenom  string
+		wantAmount sdk.Dec
+	}{
+		{
+			name:       "known denom is scaled to display units",
+			coin:       sdk.NewDecCoin("aalthea", sdk.NewInt(1500000000000000000)),
+			wantDenom:  "althea",
+			wantAmount: sdk.MustNewDecFromStr("1.5"),
+		},
+		{
+			name:       "fractional base amount is kept exactly",
+			coin:       sdk.NewDecCoinFromDec("aalthea", sdk.MustNewDecFromStr("1.5")),
+			wantDenom:  "althea",
+			wantAmount: sdk.MustNewDecFromStr("0.000000000000000002"),
+		},
+		{
+			name:       "unknown denom is returned unchanged",
+			coin:       sdk.NewDecCoin("uatom", sdk.NewInt(25)),
+			wantDenom:  "uatom",
+			wantAmount: sdk.NewDec(25),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDenom, gotAmount := ToDisplayUnits(tt.coin, metadata)
+			if gotDenom != tt.wantDenom {
+				t.Errorf("ToDisplayUnits() denom = %v, want %v", gotDenom, tt.wantDenom)
+			}
+			if !gotAmount.Equal(tt.wantAmount) {
+				t.Errorf("ToDisplayUnits() amount = %v, want %v", gotAmount, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func TestNewBalanceFromCoin(t *testing.T) {
+	metadata := testMetadata()
+	want := Balance{
+		Denom:         "aalthea",
+		Amount:        "2500000000000000000",
+		DisplayDenom:  "althea",
+		DisplayAmount: "2.500000000000000000",
+	}
+	got := NewBalanceFromCoin(sdk.NewCoin("aalthea", sdk.NewInt(2500000000000000000)), metadata)
+	if got != want {
+		t.Errorf("NewBalanceFromCoin() = %v, want %v", got, want)
+	}
+}
+
+func TestCalculateTallyStatus(t *testing.T) {
+	params := gov.TallyParams{
+		Quorum:        sdk.MustNewDecFromStr("0.334"),
+		Threshold:     sdk.MustNewDecFromStr("0.5"),
+		VetoThreshold: sdk.MustNewDecFromStr("0.334"),
+	}
+	reached, notReached := true, false
+	type args struct {
+		tally          gov.TallyResult
+		bondedTokens   sdk.Int
+		inVotingPeriod bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want TallyStatus
+	}{
+		{
+			name: "no votes",
+			args: args{
+				tally:          gov.NewTallyResult(sdk.ZeroInt(), sdk.ZeroInt(), sdk.ZeroInt(), sdk.ZeroInt()),
+				bondedTokens:   sdk.NewInt(1000),
+				inVotingPeriod: true,
+			},
+			want: TallyStatus{
+				YesPercent:        "0.000000000000000000",
+				NoPercent:         "0.000000000000000000",
+				AbstainPercent:    "0.000000000000000000",
+				NoWithVetoPercent: "0.000000000000000000",
+				Turnout:           "0.000000000000000000",
+				Quorum:            "33.400000000000000000",
+				Threshold:         "50.000000000000000000",
+				VetoThreshold:     "33.400000000000000000",
+				QuorumReached:     &notReached,
+				ProjectedOutcome:  OutcomeRejectedNoQuorum,
+			},
+		},
+		{
+			name: "quorum and threshold reached",
+			args: args{
+				tally:          gov.NewTallyResult(sdk.NewInt(300), sdk.NewInt(100), sdk.NewInt(100), sdk.ZeroInt()),
+				bondedTokens:   sdk.NewInt(1000),
+				inVotingPeriod: true,
+			},
+			want: TallyStatus{
+				YesPercent:        "60.000000000000000000",
+				NoPercent:         "20.000000000000000000",
+				AbstainPercent:    "20.000000000000000000",
+				NoWithVetoPercent: "0.000000000000000000",
+				Turnout:           "50.000000000000000000",
+				Quorum:            "33.400000000000000000",
+				Threshold:         "50.000000000000000000",
+				VetoThreshold:     "33.400000000000000000",
+				QuorumReached:     &reached,
+				ThresholdReached:  true,
+				ProjectedOutcome:  OutcomePassed,
+			},
+		},
+		{
+			name: "quorum not reached and vetoed",
+			args: args{
+				tally:          gov.NewTallyResult(sdk.NewInt(50), sdk.ZeroInt(), sdk.ZeroInt(), sdk.NewInt(50)),
+				bondedTokens:   sdk.NewInt(1000),
+				inVotingPeriod: true,
+			},
+			want: TallyStatus{
+				YesPercent:        "50.000000000000000000",
+				NoPercent:         "0.000000000000000000",
+				AbstainPercent:    "0.000000000000000000",
+				NoWithVetoPercent: "50.000000000000000000",
+				Turnout:           "10.000000000000000000",
+				Quorum:            "33.400000000000000000",
+				Threshold:         "50.000000000000000000",
+				VetoThreshold:     "33.400000000000000000",
+				Vetoed:            true,
+				QuorumReached:     &notReached,
+				ProjectedOutcome:  OutcomeRejectedNoQuorum,
+			},
+		},
+		{
+			name: "finished proposal has no projection",
+			args: args{
+				tally:          gov.NewTallyResult(sdk.NewInt(300), sdk.NewInt(100), sdk.NewInt(100), sdk.ZeroInt()),
+				bondedTokens:   sdk.NewInt(1000),
+				inVotingPeriod: false,
+			},
+			want: TallyStatus{
+				YesPercent:        "60.000000000000000000",
+				NoPercent:         "20.000000000000000000",
+				AbstainPercent:    "20.000000000000000000",
+				NoWithVetoPercent: "0.000000000000000000",
+				Quorum:            "33.400000000000000000",
+				Threshold:         "50.000000000000000000",
+				VetoThreshold:     "33.400000000000000000",
+				ThresholdReached:  true,
 			},
-			want: sdk.MustNewDecFromStr("0.0000365"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := CalculateStakingAPR(tt.args.pool, tt.args.mintProvision); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("GetStakingAPR() = %v, want %v", got, tt.want)
+			if got := CalculateTallyStatus(tt.args.tally, params, tt.args.bondedTokens, tt.args.inVotingPeriod); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CalculateTallyStatus() = %+v, want %+v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestSetProposalProgress(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	minDeposit := sdk.NewCoins(sdk.NewCoin("aalthea", sdk.NewInt(1000)))
+	tests := []struct {
+		name                string
+		proposal            Proposal
+		wantTimeRemaining   int64
+		wantDepositProgress string
+	}{
+		{
+			name: "deposit period counts down to deposit end",
+			proposal: Proposal{
+				Status:         gov.StatusDepositPeriod.String(),
+				DepositEndTime: now.Add(time.Hour),
+				TotalDeposit:   sdk.NewCoins(sdk.NewCoin("aalthea", sdk.NewInt(250))),
+			},
+			wantTimeRemaining:   3600,
+			wantDepositProgress: "25.000000000000000000",
+		},
+		{
+			name: "voting period counts down to voting end",
+			proposal: Proposal{
+				Status:        gov.StatusVotingPeriod.String(),
+				VotingEndTime: now.Add(time.Minute),
+				TotalDeposit:  sdk.NewCoins(sdk.NewCoin("aalthea", sdk.NewInt(1000))),
+			},
+			wantTimeRemaining:   60,
+			wantDepositProgress: "100.000000000000000000",
+		},
+		{
+			name: "finished proposal has no time remaining",
+			proposal: Proposal{
+				Status:        gov.StatusPassed.String(),
+				VotingEndTime: now.Add(-time.Hour),
+			},
+			wantTimeRemaining:   0,
+			wantDepositProgress: "0.000000000000000000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetProposalProgress(&tt.proposal, minDeposit, now)
+			if tt.proposal.TimeRemaining != tt.wantTimeRemaining {
+				t.Errorf("SetProposalProgress() time remaining = %v, want %v", tt.proposal.TimeRemaining, tt.wantTimeRemaining)
+			}
+			if tt.proposal.DepositProgress != tt.wantDepositProgress {
+				t.Errorf("SetProposalProgress() deposit progress = %v, want %v", tt.proposal.DepositProgress, tt.wantDepositProgress)
+			}
+		})
+	}
+}
+
+func TestCalculateCSRRevenueDeltas(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	previousRevenue := map[uint64]sdk.Int{
+		1: sdk.NewInt(100),
+		2: sdk.NewInt(50),
+	}
+	csrs := []CSR{
+		{Id: 1, Revenue: "150"},
+		{Id: 2, Revenue: "50"},
+		{Id: 3, Revenue: "10"},
+	}
+	gotDeltas, gotRevenue := CalculateCSRRevenueDeltas(previousRevenue, csrs, now)
+	wantDeltas := []CSRRevenueDelta{
+		{Id: 1, Time: now, Revenue: "150", Delta: "50"},
+	}
+	if !reflect.DeepEqual(gotDeltas, wantDeltas) {
+		t.Errorf("CalculateCSRRevenueDeltas() deltas = %v, want %v", gotDeltas, wantDeltas)
+	}
+	if len(gotRevenue) != 3 || !gotRevenue[3].Equal(sdk.NewInt(10)) || !gotRevenue[1].Equal(sdk.NewInt(150)) {
+		t.Errorf("CalculateCSRRevenueDeltas() revenue = %v", gotRevenue)
+	}
+}
+
+func TestCalculateAverageBlockTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		pastHeight   int64
+		pastTime     time.Time
+		latestHeight int64
+		latestTime   time.Time
+		want         float64
+	}{
+		{
+			name:         "average over blocks",
+			pastHeight:   100,
+			pastTime:     start,
+			latestHeight: 200,
+			latestTime:   start.Add(550 * time.Second),
+			want:         5.5,
+		},
+		{
+			name:         "same height",
+			pastHeight:   1,
+			pastTime:     start,
+			latestHeight: 1,
+			latestTime:   start,
+			want:         0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateAverageBlockTime(tt.pastHeight, tt.pastTime, tt.latestHeight, tt.latestTime); got != tt.want {
+				t.Errorf("CalculateAverageBlockTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeTokenBalances(t *testing.T) {
+	metadata := testMetadata()
+	tokenPairs := TokenPairsByDenom([]erc20.TokenPair{
+		{Erc20Address: "0x0000000000000000000000000000000000000001", Denom: "aalthea", Enabled: true},
+		{Erc20Address: "0x0000000000000000000000000000000000000002", Denom: "ibc/USDC", Enabled: true},
+		{Erc20Address: "0x0000000000000000000000000000000000000003", Denom: "ibc/USDT", Enabled: true},
+	})
+	coins := sdk.NewCoins(
+		sdk.NewCoin("aalthea", sdk.NewInt(2000000000000000000)),
+		sdk.NewCoin("uatom", sdk.NewInt(5)),
+	)
+	erc20Balances := map[string]*big.Int{
+		"aalthea":  big.NewInt(0),
+		"ibc/USDC": big.NewInt(7),
+		"ibc/USDT": big.NewInt(0),
+	}
+	got := MergeTokenBalances(coins, tokenPairs, erc20Balances, metadata)
+	want := []TokenBalance{
+		{
+			Balance:      Balance{Denom: "aalthea", Amount: "2000000000000000000", DisplayDenom: "althea", DisplayAmount: "2.000000000000000000"},
+			Erc20Address: "0x0000000000000000000000000000000000000001",
+			Erc20Amount:  "0",
+		},
+		{
+			Balance:      Balance{Denom: "ibc/USDC", Amount: "0", DisplayDenom: "ibc/USDC", DisplayAmount: "0.000000000000000000"},
+			Erc20Address: "0x0000000000000000000000000000000000000002",
+			Erc20Amount:  "7",
+		},
+		{
+			Balance: Balance{Denom: "uatom", Amount: "5", DisplayDenom: "uatom", DisplayAmount: "5.000000000000000000"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeTokenBalances() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetDenoms(t *testing.T) {
+	metadata := testMetadata()
+	metadata["ibc/USDC"] = bank.Metadata{
+		Base:    "ibc/USDC",
+		Display: "ibc/USDC",
+		DenomUnits: []*bank.DenomUnit{
+			{Denom: "ibc/USDC", Exponent: 0},
+		},
+	}
+	tokenPairs := TokenPairsByDenom([]erc20.TokenPair{
+		{Erc20Address: "0x0000000000000000000000000000000000000002", Denom: "ibc/USDC", Enabled: true},
+	})
+	got := GetDenoms(metadata, tokenPairs)
+	want := []Denom{
+		{Base: "aalthea", Display: "althea", Name: "Althea", Symbol: "ALTHEA", Decimals: 18},
+		{Base: "ibc/USDC", Display: "ibc/USDC", Erc20Address: "0x0000000000000000000000000000000000000002"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDenoms() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewTokenPairInfo(t *testing.T) {
+	tokens := []config.Token{
+		{Name: "Note", Address: "0x4e71a2e537b7f9d9413d3991d37958c0b5e1e503", Symbol: "NOTE", Decimals: 18, LogoURI: "https://canto.io/tokens/Note.svg"},
+	}
+	tests := []struct {
+		name string
+		pair erc20.TokenPair
+		want TokenPairInfo
+	}{
+		{
+			name: "listed token is joined regardless of address case",
+			pair: erc20.TokenPair{Erc20Address: "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503", Denom: "unote", Enabled: true, ContractOwner: erc20.OWNER_MODULE},
+			want: TokenPairInfo{
+				Denom:         "unote",
+				Erc20Address:  "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503",
+				Enabled:       true,
+				ContractOwner: "OWNER_MODULE",
+				Name:          "Note",
+				Symbol:        "NOTE",
+				Decimals:      18,
+				LogoURI:       "https://canto.io/tokens/Note.svg",
+			},
+		},
+		{
+			name: "unlisted token has no metadata",
+			pair: erc20.TokenPair{Erc20Address: "0x0000000000000000000000000000000000000001", Denom: "ibc/USDC", ContractOwner: erc20.OWNER_EXTERNAL},
+			want: TokenPairInfo{
+				Denom:         "ibc/USDC",
+				Erc20Address:  "0x0000000000000000000000000000000000000001",
+				ContractOwner: "OWNER_EXTERNAL",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewTokenPairInfo(tt.pair, tokens); got != tt.want {
+				t.Errorf("NewTokenPairInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertAddress(t *testing.T) {
+	want := Addresses{
+		Account: "canto1fec69efhkluajsfa8xgax72ccz67regrwhwgax",
+		Valoper: "cantovaloper1fec69efhkluajsfa8xgax72ccz67regrvffvvr",
+		Evm:     "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503",
+	}
+	tests := []struct {
+		name    string
+		address string
+		want    Addresses
+		wantErr bool
+	}{
+		{
+			name:    "checksummed hex address",
+			address: "0x4e71A2E537B7f9D9413D3991D37958c0b5e1e503",
+			want:    want,
+		},
+		{
+			name:    "lower case hex address",
+			address: "0x4e71a2e537b7f9d9413d3991d37958c0b5e1e503",
+			want:    want,
+		},
+		{
+			name:    "account address",
+			address: "canto1fec69efhkluajsfa8xgax72ccz67regrwhwgax",
+			want:    want,
+		},
+		{
+			name:    "validator operator address",
+			address: "cantovaloper1fec69efhkluajsfa8xgax72ccz67regrvffvvr",
+			want:    want,
+		},
+		{
+			name:    "invalid checksum",
+			address: "0x4E71a2E537B7f9D9413D3991D37958c0b5e1e503",
+			wantErr: true,
+		},
+		{
+			name:    "invalid bech32 checksum",
+			address: "canto1fec69efhkluajsfa8xgax72ccz67regrwhwgaa",
+			wantErr: true,
+		},
+		{
+			name:    "other chain prefix",
+			address: "cosmos1fec69efhkluajsfa8xgax72ccz67regrup0cfw",
+			wantErr: true,
+		},
+		{
+			name:    "short hex address",
+			address: "0x4e71A2E537",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertAddress(tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertAddress() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ConvertAddress() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBalancesFromDecCoins(t *testing.T) {
+	metadata := testMetadata()
+	coins := sdk.NewDecCoins(
+		sdk.NewDecCoinFromDec("aalthea", sdk.MustNewDecFromStr("2500000000000000000.5")),
+		sdk.NewDecCoin("uatom", sdk.NewInt(3)),
+	)
+	got := NewBalancesFromDecCoins(coins, metadata)
+	want := []Balance{
+		{Denom: "aalthea", Amount: "2500000000000000000.500000000000000000", DisplayDenom: "althea", DisplayAmount: "2.500000000000000000"},
+		{Denom: "uatom", Amount: "3.000000000000000000", DisplayDenom: "uatom", DisplayAmount: "3.000000000000000000"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBalancesFromDecCoins() = %+v, want %+v", got, want)
+	}
+	if got := NewBalancesFromDecCoins(sdk.DecCoins{}, metadata); len(got) != 0 || got == nil {
+		t.Errorf("NewBalancesFromDecCoins() of no coins = %v, want empty list", got)
+	}
+}
+
+func TestNewBalancesFromCoins(t *testing.T) {
+	metadata := testMetadata()
+	coins := sdk.NewCoins(
+		sdk.NewCoin("aalthea", sdk.NewInt(2500000000000000000)),
+		sdk.NewCoin("uatom", sdk.NewInt(3)),
+	)
+	got := NewBalancesFromCoins(coins, metadata)
+	want := []Balance{
+		{Denom: "aalthea", Amount: "2500000000000000000", DisplayDenom: "althea", DisplayAmount: "2.500000000000000000"},
+		{Denom: "uatom", Amount: "3", DisplayDenom: "uatom", DisplayAmount: "3.000000000000000000"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBalancesFromCoins() = %+v, want %+v", got, want)
+	}
+	if got := NewBalancesFromCoins(sdk.Coins{}, metadata); len(got) != 0 || got == nil {
+		t.Errorf("NewBalancesFromCoins() of no coins = %v, want empty list", got)
+	}
+}
+
+func TestDiffValidatorSnapshots(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	validators := []Validator{
+		{OperatorAddress: "valoper1", ConsensusAddress: "valcons1", Description: staking.Description{Moniker: "one"}, Jailed: true, Status: "BOND_STATUS_UNBONDING", Commission: "0.05"},
+		{OperatorAddress: "valoper2", ConsensusAddress: "valcons2", Description: staking.Description{Moniker: "two"}, Status: "BOND_STATUS_BONDED", Commission: "0.10"},
+		{OperatorAddress: "valoper3", ConsensusAddress: "valcons3", Description: staking.Description{Moniker: "three"}, Status: "BOND_STATUS_BONDED", Commission: "0.05"},
+	}
+	signingInfos := map[string]slashing.ValidatorSigningInfo{
+		"valcons1": {Address: "valcons1", Tombstoned: true},
+		"valcons2": {Address: "valcons2"},
+	}
+	previous := map[string]ValidatorSnapshot{
+		"valoper1": {Moniker: "one", Status: "BOND_STATUS_BONDED", Commission: "0.05"},
+		"valoper2": {Moniker: "two", Jailed: true, Status: "BOND_STATUS_BONDED", Commission: "0.05"},
+	}
+	got := DiffValidatorSnapshots(previous, NewValidatorSnapshots(validators, signingInfos), 100, now)
+	want := []ValidatorEvent{
+		{Validator: "valoper1", Moniker: "one", Type: EventJailed, Height: 100, Time: now},
+		{Validator: "valoper1", Moniker: "one", Type: EventTombstoned, Height: 100, Time: now},
+		{Validator: "valoper1", Moniker: "one", Type: EventStatusChanged, From: "BOND_STATUS_BONDED", To: "BOND_STATUS_UNBONDING", Height: 100, Time: now},
+		{Validator: "valoper2", Moniker: "two", Type: EventUnjailed, Height: 100, Time: now},
+		{Validator: "valoper2", Moniker: "two", Type: EventCommissionChanged, From: "0.05", To: "0.10", Height: 100, Time: now},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffValidatorSnapshots() = %+v, want %+v", got, want)
+	}
+	if got := DiffValidatorSnapshots(map[string]ValidatorSnapshot{}, NewValidatorSnapshots(validators, signingInfos), 100, now); len(got) != 0 {
+		t.Errorf("DiffValidatorSnapshots() without previous snapshots = %+v, want no events", got)
+	}
+}
+
+func TestFilterValidatorEvents(t *testing.T) {
+	events := []ValidatorEvent{
+		{Validator: "valoper1", Type: EventJailed},
+		{Validator: "valoper1", Type: EventUnjailed},
+		{Validator: "valoper2", Type: EventJailed},
+	}
+	tests := []struct {
+		name      string
+		validator string
+		eventType string
+		want      []ValidatorEvent
+	}{
+		{
+			name: "no filters",
+			want: events,
+		},
+		{
+			name:      "by validator",
+			validator: "valoper1",
+			want:      events[:2],
+		},
+		{
+			name:      "by type",
+			eventType: EventJailed,
+			want:      []ValidatorEvent{events[0], events[2]},
+		},
+		{
+			name:      "by validator and type",
+			validator: "valoper2",
+			eventType: EventUnjailed,
+			want:      []ValidatorEvent{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FilterValidatorEvents(events, tt.validator, tt.eventType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterValidatorEvents() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetConsensusAddress(t *testing.T) {
+	pubKey := ed25519.GenPrivKey().PubKey()
+	validator, err := staking.NewValidator(sdk.ValAddress([]byte("validator_address___")), pubKey, staking.Description{})
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+	// round trip through protobuf so the public key is packed like in a grpc response
+	bz, err := validator.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal validator: %v", err)
+	}
+	var decoded staking.Validator
+	if err := decoded.Unmarshal(bz); err != nil {
+		t.Fatalf("failed to unmarshal validator: %v", err)
+	}
+	want, _ := bech32.ConvertAndEncode("cantovalcons", pubKey.Address())
+	got, err := getConsensusAddress(decoded)
+	if err != nil {
+		t.Fatalf("getConsensusAddress() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("getConsensusAddress() = %v, want %v", got, want)
+	}
+}
+
+func TestDenomsToMetadata(t *testing.T) {
+	metadata := DenomsToMetadata([]Denom{{Base: "aalthea", Display: "althea", Symbol: "ALTHEA", Decimals: 18}})
+	balance := NewBalanceFromCoin(sdk.NewCoin("aalthea", sdk.NewInt(2500000000000000000)), metadata)
+	want := Balance{Denom: "aalthea", Amount: "2500000000000000000", DisplayDenom: "althea", DisplayAmount: sdk.MustNewDecFromStr("2.5").String()}
+	if balance != want {
+		t.Errorf("NewBalanceFromCoin() with cached denoms = %+v, want %+v", balance, want)
+	}
+	if metadata["aalthea"].Symbol != "ALTHEA" {
+		t.Errorf("DenomsToMetadata() symbol = %v, want ALTHEA", metadata["aalthea"].Symbol)
+	}
+}
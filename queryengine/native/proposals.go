@@ -1,76 +1,97 @@
 package queryengine
 
 import (
+	"encoding/json"
 	"fmt"
 
 	erc20 "github.com/Canto-Network/Canto/v6/x/erc20/types"
 	govshuttle "github.com/Canto-Network/Canto/v6/x/govshuttle/types"
+	"github.com/cosmos/cosmos-sdk/codec"
 	types1 "github.com/cosmos/cosmos-sdk/codec/types"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
 	distribution "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
 	params "github.com/cosmos/cosmos-sdk/x/params/types/proposal"
+	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
 	upgrade "github.com/cosmos/cosmos-sdk/x/upgrade/types"
 	clienttypes "github.com/cosmos/ibc-go/v3/modules/core/02-client/types"
+	ibctm "github.com/cosmos/ibc-go/v3/modules/light-clients/07-tendermint/types"
 )
 
+// proposalCodec decodes proposal content and messages of every type registered in newProposalInterfaceRegistry
+var proposalCodec = codec.NewProtoCodec(newProposalInterfaceRegistry())
+
+// newProposalInterfaceRegistry returns an interface registry with all known gov content and message types registered
+func newProposalInterfaceRegistry() types1.InterfaceRegistry {
+	registry := types1.NewInterfaceRegistry()
+	gov.RegisterInterfaces(registry)
+	bank.RegisterInterfaces(registry)
+	staking.RegisterInterfaces(registry)
+	distribution.RegisterInterfaces(registry)
+	params.RegisterInterfaces(registry)
+	upgrade.RegisterInterfaces(registry)
+	clienttypes.RegisterInterfaces(registry)
+	ibctm.RegisterInterfaces(registry)
+	erc20.RegisterInterfaces(registry)
+	govshuttle.RegisterInterfaces(registry)
+	return registry
+}
+
 type BasicMetadata struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 }
 
+// RawContent is returned as proposal content when its type is not registered
+type RawContent struct {
+	TypeUrl string `json:"@type"`
+	// value is the base64 encoded protobuf of the content
+	Value []byte `json:"value"`
+}
+
+// DecodeProposalContent unpacks proposal content into its registered gov content type
+func DecodeProposalContent(content *types1.Any) (gov.Content, error) {
+	if content == nil {
+		return nil, fmt.Errorf("Proposal content is empty")
+	}
+	var decoded gov.Content
+	err := proposalCodec.UnpackAny(content, &decoded)
+	if err != nil {
+		return nil, fmt.Errorf("Proposal type: %s not found: %w", content.TypeUrl, err)
+	}
+	return decoded, nil
+}
+
 func GetProposalMetadata(content *types1.Any) (BasicMetadata, error) {
-	typeUrl := content.TypeUrl
-	switch typeUrl {
-	case "/canto.govshuttle.v1.LendingMarketProposal":
-		var metadata govshuttle.LendingMarketProposal
-		metadata.Unmarshal(content.Value)
-		return BasicMetadata{
-			Title:       metadata.GetTitle(),
-			Description: metadata.GetDescription(),
-		}, nil
-	case "/canto.erc20.v1.RegisterCoinProposal":
-		var metadata erc20.RegisterCoinProposal
-		metadata.Unmarshal(content.Value)
-		return BasicMetadata{
-			Title:       metadata.GetTitle(),
-			Description: metadata.GetDescription(),
-		}, nil
-	case "/cosmos.distribution.v1beta1.CommunityPoolSpendProposal":
-		var metadata distribution.CommunityPoolSpendProposal
-		metadata.Unmarshal(content.Value)
-		return BasicMetadata{
-			Title:       metadata.GetTitle(),
-			Description: metadata.GetDescription(),
-		}, nil
-	case "/cosmos.gov.v1beta1.TextProposal":
-		var metadata gov.TextProposal
-		metadata.Unmarshal(content.Value)
-		return BasicMetadata{
-			Title:       metadata.GetTitle(),
-			Description: metadata.GetDescription(),
-		}, nil
-	case "/cosmos.params.v1beta1.ParameterChangeProposal":
-		var metadata params.ParameterChangeProposal
-		metadata.Unmarshal(content.Value)
-		return BasicMetadata{
-			Title:       metadata.GetTitle(),
-			Description: metadata.GetDescription(),
-		}, nil
-	case "/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal":
-		var metadata upgrade.SoftwareUpgradeProposal
-		metadata.Unmarshal(content.Value)
-		return BasicMetadata{
-			Title:       metadata.GetTitle(),
-			Description: metadata.GetDescription(),
-		}, nil
-	case "/ibc.core.client.v1.ClientUpdateProposal":
-		var metadata clienttypes.ClientUpdateProposal
-		metadata.Unmarshal(content.Value)
-		return BasicMetadata{
-			Title:       metadata.GetTitle(),
-			Description: metadata.GetDescription(),
-		}, nil
-	default:
-		return BasicMetadata{}, fmt.Errorf("Proposal type: %s not found", typeUrl)
+	decoded, err := DecodeProposalContent(content)
+	if err != nil {
+		return BasicMetadata{}, err
+	}
+	return BasicMetadata{
+		Title:       decoded.GetTitle(),
+		Description: decoded.GetDescription(),
+	}, nil
+}
+
+// GetProposalContent returns the full json of proposal content, falling back to
+// the type url and base64 encoded value if the content type is not registered
+func GetProposalContent(content *types1.Any) json.RawMessage {
+	return AnyToJSON(content)
+}
+
+// AnyToJSON returns the json of a packed message or proposal content, falling back to
+// the type url and base64 encoded value if the type is not registered
+func AnyToJSON(value *types1.Any) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	// the codec resolves the type url (and any nested Any) through the registry
+	valueJson, err := proposalCodec.MarshalJSON(value)
+	if err == nil {
+		return valueJson
 	}
+	return json.RawMessage(GeneralResultToString(RawContent{
+		TypeUrl: value.TypeUrl,
+		Value:   value.Value,
+	}))
 }
@@ -0,0 +1,107 @@
+package queryengine
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	govshuttle "github.com/Canto-Network/Canto/v6/x/govshuttle/types"
+	types1 "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	distribution "github.com/cosmos/cosmos-sdk/x/distribution/types"
+	upgrade "github.com/cosmos/cosmos-sdk/x/upgrade/types"
+)
+
+func mustPackContent(t *testing.T, content interface{}) *types1.Any {
+	any, err := types1.NewAnyWithValue(content.(interface {
+		Reset()
+		String() string
+		ProtoMessage()
+	}))
+	if err != nil {
+		t.Fatalf("failed to pack content: %v", err)
+	}
+	// drop the cached value so content is decoded from bytes like a grpc response
+	return &types1.Any{TypeUrl: any.TypeUrl, Value: any.Value}
+}
+
+func TestGetProposalMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *types1.Any
+		want    BasicMetadata
+		wantErr bool
+	}{
+		{
+			name:    "registered canto proposal type",
+			content: mustPackContent(t, &govshuttle.LendingMarketProposal{Title: "lending", Description: "add market"}),
+			want:    BasicMetadata{Title: "lending", Description: "add market"},
+		},
+		{
+			name:    "cancel software upgrade proposal",
+			content: mustPackContent(t, upgrade.NewCancelSoftwareUpgradeProposal("cancel", "cancel upgrade")),
+			want:    BasicMetadata{Title: "cancel", Description: "cancel upgrade"},
+		},
+		{
+			name:    "unknown proposal type",
+			content: &types1.Any{TypeUrl: "/canto.unknown.v1.Proposal", Value: []byte{0x0a, 0x01, 0x61}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetProposalMetadata(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetProposalMetadata() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetProposalMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProposalContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content *types1.Any
+		want    map[string]interface{}
+	}{
+		{
+			name: "community pool spend includes recipient and amount",
+			content: mustPackContent(t, distribution.NewCommunityPoolSpendProposal(
+				"spend", "fund", sdk.AccAddress([]byte("recipient_address___")),
+				sdk.NewCoins(sdk.NewCoin("aalthea", sdk.NewInt(10))),
+			)),
+			want: map[string]interface{}{
+				"@type":       "/cosmos.distribution.v1beta1.CommunityPoolSpendProposal",
+				"title":       "spend",
+				"description": "fund",
+				"recipient":   sdk.AccAddress([]byte("recipient_address___")).String(),
+				"amount": []interface{}{
+					map[string]interface{}{"denom": "aalthea", "amount": "10"},
+				},
+			},
+		},
+		{
+			name:    "unknown proposal type falls back to base64",
+			content: &types1.Any{TypeUrl: "/canto.unknown.v1.Proposal", Value: []byte{0x0a, 0x01, 0x61}},
+			want: map[string]interface{}{
+				"@type": "/canto.unknown.v1.Proposal",
+				"value": "CgFh",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			if err := json.Unmarshal(GetProposalContent(tt.content), &got); err != nil {
+				t.Fatalf("GetProposalContent() returned invalid json: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetProposalContent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
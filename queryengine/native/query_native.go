@@ -2,15 +2,23 @@ package queryengine
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"althea-api/config"
+	"althea-api/webhooks"
 
 	csr "github.com/Canto-Network/Canto/v6/x/csr/types"
+	erc20 "github.com/Canto-Network/Canto/v6/x/erc20/types"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
 	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
 	minttypes "github.com/cosmos/cosmos-sdk/x/mint/types" // Import the Cosmos SDK's mint types
+	slashing "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
@@ -25,6 +33,19 @@ type NativeQueryEngine struct {
 	InflationQueryHandler minttypes.QueryClient // Use the correct QueryClient type from the Cosmos SDK's mint module
 	StakingQueryHandler   staking.QueryClient
 	DistributionQueryHandler distrtypes.QueryClient
+	BankQueryHandler      bank.QueryClient
+	GovV1QueryHandler     GovV1QueryClient
+	TendermintQueryHandler tmservice.ServiceClient
+	Erc20QueryHandler     erc20.QueryClient
+	SlashingQueryHandler  slashing.QueryClient
+	// revenue of each CSR NFT at the previous tick
+	csrRevenue map[uint64]sdk.Int
+	// webhooks notified of conditions over the data of each tick
+	webhooks *webhooks.Dispatcher
+	// staking APR at the last notification of each webhook with a staking APR rule
+	stakingAprBaselines map[string]sdk.Dec
+	// number of ticks run, used to refresh per validator data on a slower cadence
+	ticks uint64
 }
 
 // Returns a NativeQueryEngine instance
@@ -37,6 +58,14 @@ func NewNativeQueryEngine() *NativeQueryEngine {
 		InflationQueryHandler: minttypes.NewQueryClient(config.GrpcClient), // Use the NewQueryClient function from the Cosmos SDK's mint module
 		StakingQueryHandler:   staking.NewQueryClient(config.GrpcClient),
 		DistributionQueryHandler: distrtypes.NewQueryClient(config.GrpcClient),
+		BankQueryHandler:      bank.NewQueryClient(config.GrpcClient),
+		GovV1QueryHandler:     NewGovV1QueryClient(config.GrpcClient),
+		TendermintQueryHandler: tmservice.NewServiceClient(config.GrpcClient),
+		Erc20QueryHandler:     erc20.NewQueryClient(config.GrpcClient),
+		SlashingQueryHandler:  slashing.NewQueryClient(config.GrpcClient),
+		csrRevenue:            make(map[uint64]sdk.Int),
+		webhooks:              webhooks.NewDispatcher(),
+		stakingAprBaselines:   make(map[string]sdk.Dec),
 	}
 }
 
@@ -65,6 +94,19 @@ func (nqe *NativeQueryEngine) SetMapToCache(ctx context.Context, key string, res
 	return nil
 }
 
+// replace mapping in cache, dropping fields that are not in the new result
+func (nqe *NativeQueryEngine) ReplaceMapInCache(ctx context.Context, key string, result map[string]string) error {
+	_, err := nqe.redisclient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, result)
+		return nil
+	})
+	if err != nil {
+		return errors.New("ReplaceMapInCache: " + err.Error())
+	}
+	return nil
+}
+
 func nativeQueryEngineFatalLog(err error, function string, msg string) {
 	log.Fatal().
 		Err(err).
@@ -77,10 +119,16 @@ func nativeQueryEngineFatalLog(err error, function string, msg string) {
 func (nqe *NativeQueryEngine) StartNativeQueryEngine(ctx context.Context) {
 	ticker := time.NewTicker(nqe.interval * time.Second)
 	for range ticker.C {
+		// denom metadata is used to return amounts in display units
+		metadata, err := GetDenomsMetadata(ctx, nqe.BankQueryHandler)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetDenomsMetadata").Msg("Failed to get denoms metadata")
+		}
+
 		//
 		// STAKING
 		//
-		stakingApr, err := GetStakingAPR(ctx, nqe.StakingQueryHandler, nqe.InflationQueryHandler)
+		stakingApr, err := GetStakingAPR(ctx, nqe.StakingQueryHandler, nqe.InflationQueryHandler, nqe.DistributionQueryHandler)
         if err != nil {
             log.Error().Err(err).Str("func", "GetStakingAPR").Msg("Failed to get staking APR")
             continue // Skip this iteration on error
@@ -95,8 +143,9 @@ func (nqe *NativeQueryEngine) StartNativeQueryEngine(ctx context.Context) {
             log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set staking APR in cache")
             // Handle the error or continue based on your error handling strategy
         }
+		nqe.notifyStakingAPR(ctx, stakingApr, time.Now())
 		// get and save all validators to cache
-		validators, validatorMap, err := GetValidators(ctx, nqe.StakingQueryHandler)
+		validators, validatorMap, err := GetValidators(ctx, nqe.StakingQueryHandler, stakingApr)
 		if err != nil {
 			nativeQueryEngineFatalLog(err, "StartNativeQueryEngine", "failed to get validators")
 		}
@@ -109,30 +158,160 @@ func (nqe *NativeQueryEngine) StartNativeQueryEngine(ctx context.Context) {
 			nativeQueryEngineFatalLog(err, "StartNativeQueryEngine", "failed to set validator map")
 		}
 
+		// per validator queries are refreshed on a slower cadence
+		refreshValidators := nqe.ticks%config.ValidatorDelegationsRefreshTicks == 0
+		nqe.ticks++
+		if refreshValidators {
+			nqe.refreshValidatorDelegations(ctx, validators, metadata)
+		}
+
+		//
+		// CHAIN
+		//
+		chainStatus, err := GetChainStatus(ctx, nqe.TendermintQueryHandler, nqe.InflationQueryHandler, nqe.BankQueryHandler, nqe.StakingQueryHandler, nqe.DistributionQueryHandler, validators, metadata)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetChainStatus").Msg("Failed to get chain status")
+		} else {
+			err = nqe.SetJsonToCache(ctx, config.ChainStatus, chainStatus)
+			if err != nil {
+				log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set chain status")
+			}
+			// publish the measured block time so both engines use the same value
+			if chainStatus.AverageBlockTime > 0 {
+				err = nqe.redisclient.Set(ctx, config.AverageBlockTime, chainStatus.AverageBlockTime, config.AverageBlockTimeExpiration).Err()
+				if err != nil {
+					log.Error().Err(err).Str("func", "StartNativeQueryEngine").Msg("Failed to set average block time")
+				}
+			}
+		}
+
+		//
+		// VALIDATOR EVENTS
+		//
+		// events are stamped with the latest block they were detected at, so they are only derived when the chain status is known.
+		// snapshots of the previous tick are kept in cache, so changes made during a restart are still detected.
+		signingInfos, err := GetSigningInfos(ctx, nqe.SlashingQueryHandler)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetSigningInfos").Msg("Failed to get signing infos")
+		} else if chainStatus.LatestHeight > 0 {
+			nqe.diffValidatorSnapshots(ctx, NewValidatorSnapshots(validators, signingInfos), chainStatus)
+		}
+
+		//
+		// BANK
+		//
+		tokenPairs, err := GetTokenPairs(ctx, nqe.Erc20QueryHandler)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetTokenPairs").Msg("Failed to get token pairs")
+		}
+		tokenPairsByDenom := TokenPairsByDenom(tokenPairs)
+		supply, err := GetTotalSupply(ctx, nqe.BankQueryHandler, metadata, tokenPairsByDenom)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetTotalSupply").Msg("Failed to get total supply")
+		} else {
+			err = nqe.SetJsonToCache(ctx, config.BankSupply, supply)
+			if err != nil {
+				log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set total supply")
+			}
+		}
+		if len(metadata) > 0 {
+			err = nqe.SetJsonToCache(ctx, config.BankDenoms, GetDenoms(metadata, tokenPairsByDenom))
+			if err != nil {
+				log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set denoms")
+			}
+		}
+
+		//
+		// DISTRIBUTION
+		//
+		communityPool, err := GetCommunityPool(ctx, nqe.DistributionQueryHandler, metadata)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetCommunityPool").Msg("Failed to get community pool")
+		} else {
+			err = nqe.SetJsonToCache(ctx, config.CommunityPool, communityPool)
+			if err != nil {
+				log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set community pool")
+			}
+		}
+		// refresh commission and outstanding rewards of every validator on the same cadence as delegations
+		if refreshValidators {
+			nqe.refreshValidatorDistribution(ctx, validators, metadata)
+		}
+
 		//
 		// CSR
 		//
-		// csrs, csrMap, err := GetCSRS(ctx, nqe.CSRQueryHandler)
-		// if err != nil {
-		// 	nativeQueryEngineFatalLog(err, "StartNativeQueryEngine", "failed to get CSRs")
-		// }
-		// err = nqe.SetJsonToCache(ctx, config.AllCSRs, csrs)
-		// if err != nil {
-		// 	nativeQueryEngineFatalLog(err, "StartNativeQueryEngine", "failed to set CSRs")
-		// }
-		// err = nqe.SetMapToCache(ctx, config.CSRMap, csrMap)
-		// if err != nil {
-		// 	nativeQueryEngineFatalLog(err, "StartNativeQueryEngine", "failed to set CSR map")
-		// }
+		csrs, csrMap, err := GetCSRS(ctx, nqe.CSRQueryHandler)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetCSRS").Msg("Failed to get CSRs")
+		} else {
+			err = nqe.SetJsonToCache(ctx, config.AllCSRs, csrs)
+			if err != nil {
+				log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set CSRs")
+			}
+			if len(csrMap) > 0 {
+				err = nqe.SetMapToCache(ctx, config.CSRMap, csrMap)
+				if err != nil {
+					log.Error().Err(err).Str("func", "SetMapToCache").Msg("Failed to set CSR map")
+				}
+			}
+			// save revenue earned by each NFT since the previous tick
+			var revenueDeltas []CSRRevenueDelta
+			revenueDeltas, nqe.csrRevenue = CalculateCSRRevenueDeltas(nqe.csrRevenue, csrs, time.Now())
+			for _, delta := range revenueDeltas {
+				err = config.PushToListCache(ctx, nqe.redisclient, fmt.Sprintf("%s:%d", config.CSRRevenueHistory, delta.Id), config.MaxHistoryLength, delta)
+				if err != nil {
+					log.Error().Err(err).Str("func", "PushToListCache").Msgf("Failed to set revenue history of CSR %d", delta.Id)
+				}
+			}
+		}
+		csrParams, err := GetCSRParams(ctx, nqe.CSRQueryHandler)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetCSRParams").Msg("Failed to get CSR params")
+		} else {
+			err = nqe.SetJsonToCache(ctx, config.CSRParams, csrParams)
+			if err != nil {
+				log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set CSR params")
+			}
+		}
+		turnstile, err := GetTurnstileAddress(ctx, nqe.CSRQueryHandler)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetTurnstileAddress").Msg("Failed to get turnstile address")
+		} else {
+			err = nqe.SetJsonToCache(ctx, config.CSRTurnstile, turnstile)
+			if err != nil {
+				log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set turnstile address")
+			}
+		}
 
 		//
 		// GOVSHUTTLE
 		//
-		proposals, proposalMap, err := GetAllProposals(ctx, nqe.GovQueryHandler)
-        if err != nil {
-            log.Error().Err(err).Str("func", "GetAllProposals").Msg("Failed to get proposals")
-            continue // Skip this iteration on error
-        }
+		govParams, bondedTokens, err := getGovParamsAndBondedTokens(ctx, nqe.GovQueryHandler, nqe.StakingQueryHandler, metadata)
+		if err != nil {
+			log.Error().Err(err).Str("func", "getGovParamsAndBondedTokens").Msg("Failed to get gov params")
+			continue // Skip this iteration on error
+		}
+		err = nqe.SetJsonToCache(ctx, config.GovParams, govParams)
+		if err != nil {
+			log.Error().Err(err).Str("func", "SetJsonToCache").Msg("Failed to set gov params")
+		}
+
+		// merge v1beta1 and gov v1 proposals, chains without gov v1 only serve v1beta1 proposals
+		// the iteration is only skipped if neither can be fetched
+		proposals, _, err := GetAllProposals(ctx, nqe.GovQueryHandler, govParams, bondedTokens)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetAllProposals").Msg("Failed to get proposals")
+		}
+		v1Proposals, v1Err := GetAllV1Proposals(ctx, nqe.GovV1QueryHandler, nqe.GovQueryHandler, govParams, bondedTokens)
+		if v1Err != nil {
+			log.Warn().Err(v1Err).Str("func", "GetAllV1Proposals").Msg("Failed to get gov v1 proposals")
+		}
+		if err != nil && v1Err != nil {
+			continue // Skip this iteration on error
+		}
+		proposals = MergeProposals(proposals, v1Proposals)
+		proposalMap := ProposalsToMap(proposals)
 
         err = nqe.SetJsonToCache(ctx, config.AllProposals, proposals)
         if err != nil {
@@ -148,9 +327,121 @@ func (nqe *NativeQueryEngine) StartNativeQueryEngine(ctx context.Context) {
                 // Handle the error or continue based on your error handling strategy
             }
         }
+		nqe.notifyProposalVoting(ctx, proposals)
     }
 }
 
+// get the validator snapshots of the previous tick from cache, empty if none were saved yet
+func (nqe *NativeQueryEngine) GetValidatorSnapshots(ctx context.Context) (map[string]ValidatorSnapshot, error) {
+	snapshots := make(map[string]ValidatorSnapshot)
+	val, err := nqe.redisclient.Get(ctx, config.ValidatorSnapshots).Result()
+	if err == redis.Nil {
+		return snapshots, nil
+	}
+	if err != nil {
+		return nil, errors.New("GetValidatorSnapshots: " + err.Error())
+	}
+	err = json.Unmarshal([]byte(val), &snapshots)
+	if err != nil {
+		return nil, errors.New("GetValidatorSnapshots: " + err.Error())
+	}
+	return snapshots, nil
+}
+
+// diffValidatorSnapshots saves the events between the cached and current validator snapshots,
+// then replaces the cached snapshots with the current ones
+func (nqe *NativeQueryEngine) diffValidatorSnapshots(ctx context.Context, snapshots map[string]ValidatorSnapshot, chainStatus ChainStatus) {
+	previous, err := nqe.GetValidatorSnapshots(ctx)
+	if err != nil {
+		// keep the cached snapshots, so the events are detected on the next tick
+		log.Error().Err(err).Str("func", "GetValidatorSnapshots").Msg("Failed to get validator snapshots")
+		return
+	}
+	events := DiffValidatorSnapshots(previous, snapshots, chainStatus.LatestHeight, chainStatus.LatestTime)
+	nqe.notifyValidatorJailed(ctx, events)
+	for _, event := range events {
+		err = config.PushToListCache(ctx, nqe.redisclient, config.ValidatorEvents, config.MaxHistoryLength, event)
+		if err != nil {
+			log.Error().Err(err).Str("func", "PushToListCache").Msgf("Failed to set %s event of %s", event.Type, event.Validator)
+		}
+	}
+	err = nqe.redisclient.Set(ctx, config.ValidatorSnapshots, GeneralResultToString(snapshots), 0).Err()
+	if err != nil {
+		log.Error().Err(err).Str("func", "diffValidatorSnapshots").Msg("Failed to set validator snapshots")
+	}
+}
+
+// refreshValidatorDelegations gets delegations and unbondings of every validator and replaces
+// the cached maps, so validators that left the set are dropped
+func (nqe *NativeQueryEngine) refreshValidatorDelegations(ctx context.Context, validators []Validator, metadata DenomMetadataMap) {
+	validatorDelegationsMap := make(map[string]string)
+	validatorUnbondingsMap := make(map[string]string)
+	bondDenom, err := GetBondDenom(ctx, nqe.StakingQueryHandler)
+	if err != nil {
+		log.Error().Err(err).Str("func", "GetBondDenom").Msg("Failed to get bond denom")
+		return
+	}
+	for _, validator := range validators {
+		delegations, err := GetValidatorDelegations(ctx, nqe.StakingQueryHandler, validator.OperatorAddress, metadata)
+		if err != nil {
+			// keep the previous maps rather than dropping this validator
+			log.Error().Err(err).Str("func", "GetValidatorDelegations").Msgf("Failed to get delegations for %s", validator.OperatorAddress)
+			return
+		}
+		unbondings, err := GetValidatorUnbondings(ctx, nqe.StakingQueryHandler, validator.OperatorAddress, bondDenom, metadata)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetValidatorUnbondings").Msgf("Failed to get unbondings for %s", validator.OperatorAddress)
+			return
+		}
+		validatorDelegationsMap[validator.OperatorAddress] = GeneralResultToString(delegations)
+		validatorUnbondingsMap[validator.OperatorAddress] = GeneralResultToString(unbondings)
+	}
+	if len(validatorDelegationsMap) == 0 {
+		return
+	}
+	err = nqe.ReplaceMapInCache(ctx, config.ValidatorDelegationsMap, validatorDelegationsMap)
+	if err != nil {
+		log.Error().Err(err).Str("func", "ReplaceMapInCache").Msg("Failed to set validator delegations map")
+	}
+	err = nqe.ReplaceMapInCache(ctx, config.ValidatorUnbondingsMap, validatorUnbondingsMap)
+	if err != nil {
+		log.Error().Err(err).Str("func", "ReplaceMapInCache").Msg("Failed to set validator unbondings map")
+	}
+}
+
+// refreshValidatorDistribution gets commission and outstanding rewards of every validator and replaces
+// the cached maps, so validators that left the set are dropped
+func (nqe *NativeQueryEngine) refreshValidatorDistribution(ctx context.Context, validators []Validator, metadata DenomMetadataMap) {
+	validatorCommissionMap := make(map[string]string)
+	validatorOutstandingRewardsMap := make(map[string]string)
+	for _, validator := range validators {
+		commission, err := GetValidatorCommission(ctx, nqe.DistributionQueryHandler, validator.OperatorAddress, metadata)
+		if err != nil {
+			// keep the previous maps rather than dropping this validator
+			log.Error().Err(err).Str("func", "GetValidatorCommission").Msgf("Failed to get commission for %s", validator.OperatorAddress)
+			return
+		}
+		outstandingRewards, err := GetValidatorOutstandingRewards(ctx, nqe.DistributionQueryHandler, validator.OperatorAddress, metadata)
+		if err != nil {
+			log.Error().Err(err).Str("func", "GetValidatorOutstandingRewards").Msgf("Failed to get outstanding rewards for %s", validator.OperatorAddress)
+			return
+		}
+		validatorCommissionMap[validator.OperatorAddress] = GeneralResultToString(commission)
+		validatorOutstandingRewardsMap[validator.OperatorAddress] = GeneralResultToString(outstandingRewards)
+	}
+	if len(validatorCommissionMap) == 0 {
+		return
+	}
+	err := nqe.ReplaceMapInCache(ctx, config.ValidatorCommissionMap, validatorCommissionMap)
+	if err != nil {
+		log.Error().Err(err).Str("func", "ReplaceMapInCache").Msg("Failed to set validator commission map")
+	}
+	err = nqe.ReplaceMapInCache(ctx, config.ValidatorOutstandingRewardsMap, validatorOutstandingRewardsMap)
+	if err != nil {
+		log.Error().Err(err).Str("func", "ReplaceMapInCache").Msg("Failed to set validator outstanding rewards map")
+	}
+}
+
 // RunNative initializes a NativeQueryEngine and starts it
 func Run(ctx context.Context) {
 	nqe := NewNativeQueryEngine()
@@ -0,0 +1,85 @@
+package queryengine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"althea-api/config"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
+	"github.com/rs/zerolog/log"
+)
+
+// votingProposals returns the proposals in their voting period
+func votingProposals(proposals []Proposal) []Proposal {
+	voting := []Proposal{}
+	for _, proposal := range proposals {
+		if proposal.Status == gov.StatusVotingPeriod.String() {
+			voting = append(voting, proposal)
+		}
+	}
+	return voting
+}
+
+// jailedEvents returns the jailing events, of a single validator if validator is not empty
+func jailedEvents(events []ValidatorEvent, validator string) []ValidatorEvent {
+	return FilterValidatorEvents(events, validator, EventJailed)
+}
+
+// StakingAPRMoved checks if the staking APR moved by at least threshold percentage points from the baseline
+func StakingAPRMoved(baseline sdk.Dec, current sdk.Dec, threshold float64) bool {
+	thresholdDec, err := sdk.NewDecFromStr(fmt.Sprintf("%f", threshold))
+	if err != nil {
+		return false
+	}
+	return current.Sub(baseline).Abs().GTE(thresholdDec)
+}
+
+// notify webhooks of proposals entering their voting period, once per proposal
+func (nqe *NativeQueryEngine) notifyProposalVoting(ctx context.Context, proposals []Proposal) {
+	for _, match := range nqe.webhooks.Rules(config.RuleProposalVoting) {
+		for _, proposal := range votingProposals(proposals) {
+			nqe.webhooks.Notify(ctx, match, fmt.Sprintf("proposal-voting:%d", proposal.ProposalId), proposal)
+		}
+	}
+}
+
+// notify webhooks of validators getting jailed
+func (nqe *NativeQueryEngine) notifyValidatorJailed(ctx context.Context, events []ValidatorEvent) {
+	for _, match := range nqe.webhooks.Rules(config.RuleValidatorJailed) {
+		validator := ""
+		if match.Rule.Address != "" {
+			addresses, err := ConvertAddress(match.Rule.Address)
+			if err != nil {
+				log.Error().Err(err).Str("func", "notifyValidatorJailed").Msgf("Invalid validator address in rule of webhook %s", match.Webhook.Name)
+				continue
+			}
+			validator = addresses.Valoper
+		}
+		for _, event := range jailedEvents(events, validator) {
+			nqe.webhooks.Notify(ctx, match, fmt.Sprintf("validator-jailed:%s:%d", event.Validator, event.Height), event)
+		}
+	}
+}
+
+// notify webhooks of staking APR moving since their last notification,
+// the first APR seen is the baseline of each webhook
+func (nqe *NativeQueryEngine) notifyStakingAPR(ctx context.Context, stakingApr sdk.Dec, now time.Time) {
+	for _, match := range nqe.webhooks.Rules(config.RuleStakingAPRChange) {
+		baseline, ok := nqe.stakingAprBaselines[match.Webhook.Name]
+		if !ok {
+			nqe.stakingAprBaselines[match.Webhook.Name] = stakingApr
+			continue
+		}
+		if !StakingAPRMoved(baseline, stakingApr, match.Rule.Threshold) {
+			continue
+		}
+		nqe.webhooks.Notify(ctx, match, fmt.Sprintf("staking-apr:%d", now.Unix()), map[string]string{
+			"previous": baseline.String(),
+			"current":  stakingApr.String(),
+		})
+		nqe.stakingAprBaselines[match.Webhook.Name] = stakingApr
+	}
+}
@@ -0,0 +1,90 @@
+package queryengine
+
+import (
+	"reflect"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types"
+)
+
+func TestVotingProposals(t *testing.T) {
+	proposals := []Proposal{
+		{ProposalId: 1, Status: gov.StatusPassed.String()},
+		{ProposalId: 2, Status: gov.StatusVotingPeriod.String()},
+		{ProposalId: 3, Status: gov.StatusDepositPeriod.String()},
+	}
+	want := []Proposal{proposals[1]}
+	if got := votingProposals(proposals); !reflect.DeepEqual(got, want) {
+		t.Errorf("votingProposals() = %v, want %v", got, want)
+	}
+}
+
+func TestJailedEvents(t *testing.T) {
+	events := []ValidatorEvent{
+		{Validator: "valoper1", Type: EventJailed},
+		{Validator: "valoper1", Type: EventCommissionChanged},
+		{Validator: "valoper2", Type: EventJailed},
+	}
+	tests := []struct {
+		name      string
+		validator string
+		want      []ValidatorEvent
+	}{
+		{
+			name: "all validators",
+			want: []ValidatorEvent{events[0], events[2]},
+		},
+		{
+			name:      "single validator",
+			validator: "valoper2",
+			want:      []ValidatorEvent{events[2]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jailedEvents(events, tt.validator); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jailedEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStakingAPRMoved(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseline  sdk.Dec
+		current   sdk.Dec
+		threshold float64
+		want      bool
+	}{
+		{
+			name:      "moved up by threshold",
+			baseline:  sdk.MustNewDecFromStr("10"),
+			current:   sdk.MustNewDecFromStr("10.5"),
+			threshold: 0.5,
+			want:      true,
+		},
+		{
+			name:      "moved down past threshold",
+			baseline:  sdk.MustNewDecFromStr("10"),
+			current:   sdk.MustNewDecFromStr("9"),
+			threshold: 0.5,
+			want:      true,
+		},
+		{
+			name:      "moved less than threshold",
+			baseline:  sdk.MustNewDecFromStr("10"),
+			current:   sdk.MustNewDecFromStr("10.4"),
+			threshold: 0.5,
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StakingAPRMoved(tt.baseline, tt.current, tt.threshold); got != tt.want {
+				t.Errorf("StakingAPRMoved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
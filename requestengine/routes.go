@@ -38,6 +38,19 @@ func routerCTokens(app *fiber.App) {
 	lending := app.Group("/v1/lending")
 	lending.Get("/ctokens", QueryCTokens)
 	lending.Get("/ctoken/:address", QueryCTokenByAddress)
+	lending.Get("/ctoken/:address/rate-curve", QueryCTokenRateCurve)
+}
+
+func routerPrices(app *fiber.App) {
+	prices := app.Group("/v1/prices")
+	prices.Get("/", QueryPrices)
+	prices.Get("/:token", QueryPriceByToken)
+}
+
+func routerStats(app *fiber.App) {
+	stats := app.Group("/v1/stats")
+	stats.Get("/", QueryStats)
+	stats.Get("/history", QueryStatsHistory)
 }
 
 func routerPairs(app *fiber.App) {
@@ -49,13 +62,21 @@ func routerPairs(app *fiber.App) {
 func routerCSR(app *fiber.App) {
 	csr := app.Group("/v1/csr")
 	csr.Get("/", QueryCSRs)
+	csr.Get("/params", QueryCSRParams)
+	csr.Get("/turnstile", QueryTurnstile)
+	csr.Get("/contract/:address", QueryCSRByContract)
 	csr.Get("/:id", QueryCSRByID)
+	csr.Get("/:id/revenue", QueryCSRRevenueHistory)
 }
 
 func routerGovernance(app *fiber.App) {
 	gov := app.Group("/v1/gov")
+	gov.Get("/params", QueryGovParams)
 	gov.Get("/proposals", QueryProposals)
 	gov.Get("/proposals/:id", QueryProposalByID)
+	gov.Get("/proposals/:id/votes", QueryProposalVotes)
+	gov.Get("/proposals/:id/deposits", QueryProposalDeposits)
+	gov.Get("/proposals/:id/vote/:voter", QueryProposalVote)
 }
 
 func routerStaking(app *fiber.App) {
@@ -63,7 +84,39 @@ func routerStaking(app *fiber.App) {
 	staking.Get("/apr", QueryStakingAPR)
 	staking.Get("/validators", QueryValidators)
 	staking.Get("/validators/:address", QueryValidatorByAddress)
+	staking.Get("/validators/:address/delegations", QueryValidatorDelegations)
+	staking.Get("/validators/:address/unbondings", QueryValidatorUnbondings)
 	staking.Get("/delegations/:address", QueryDelegationsByAddress)
+	staking.Get("/events", QueryValidatorEvents)
+}
+
+func routerBank(app *fiber.App) {
+	bank := app.Group("/v1/bank")
+	bank.Get("/balances/:address", QueryBalancesByAddress)
+	bank.Get("/supply", QuerySupply)
+	bank.Get("/denoms", QueryDenoms)
+}
+
+func routerErc20(app *fiber.App) {
+	erc20 := app.Group("/v1/erc20")
+	erc20.Get("/token-pairs", QueryTokenPairs)
+}
+
+func routerAddress(app *fiber.App) {
+	address := app.Group("/v1/address")
+	address.Get("/:address", QueryAddress)
+}
+
+func routerDistribution(app *fiber.App) {
+	distribution := app.Group("/v1/distribution")
+	distribution.Get("/community-pool", QueryCommunityPool)
+	distribution.Get("/validators/:address/commission", QueryValidatorCommission)
+	distribution.Get("/validators/:address/outstanding-rewards", QueryValidatorOutstandingRewards)
+}
+
+func routerChain(app *fiber.App) {
+	chain := app.Group("/v1/chain")
+	chain.Get("/status", QueryChainStatus)
 }
 
 // @title Canto API
@@ -90,11 +143,18 @@ func Run(ctx context.Context) {
 		app.Get(route, GetGeneralContractDataFiber)
 	}
 
+	routerChain(app)
+	routerAddress(app)
+	routerBank(app)
+	routerErc20(app)
+	routerDistribution(app)
 	routerCSR(app)
 	routerGovernance(app)
 	routerStaking(app)
 	routerPairs(app)
 	routerCTokens(app)
+	routerPrices(app)
+	routerStats(app)
 
 	app.Get("/swagger/*", swagger.HandlerDefault) // default
 
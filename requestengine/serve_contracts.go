@@ -3,6 +3,8 @@ package requestengine
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"althea-api/config"
@@ -66,8 +68,13 @@ func QueryPairByAddress(ctx *fiber.Ctx) error {
 		return RedisKeyNotFound(ctx, config.BlockNumber)
 	}
 
+	// parse address
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
 	// get pair json string from cache
-	pairString, err := config.RDB.HGet(context.Background(), config.ProcessedPairsMap, ctx.Params("address")).Result()
+	pairString, err := config.RDB.HGet(context.Background(), config.ProcessedPairsMap, config.GetConfiguredAddress(addresses.Evm)).Result()
 	if err != nil {
 		return RedisKeyNotFound(ctx, config.ProcessedPairsMap)
 	}
@@ -115,8 +122,13 @@ func QueryCTokenByAddress(ctx *fiber.Ctx) error {
 		return RedisKeyNotFound(ctx, config.BlockNumber)
 	}
 
+	// parse address
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
 	// get cToken json string from cache
-	cTokenString, err := config.RDB.HGet(context.Background(), config.ProcessedCTokensMap, ctx.Params("address")).Result()
+	cTokenString, err := config.RDB.HGet(context.Background(), config.ProcessedCTokensMap, config.GetConfiguredAddress(addresses.Evm)).Result()
 	if err != nil {
 		return RedisKeyNotFound(ctx, config.ProcessedCTokensMap)
 	}
@@ -132,3 +144,159 @@ func QueryCTokenByAddress(ctx *fiber.Ctx) error {
 	})
 	return ctx.Status(StatusOkay).SendString(result)
 }
+
+// QueryCTokenRateCurve godoc
+// @Summary      Query interest rate curve of a cToken
+// @Description  return json array of borrow and supply apy sampled from 0 to 100% utilization
+// @Accept       json
+// @Produce      json
+// @Param        address path string true "cToken address"
+// @Success      200  {object}  string
+// @Router       /lending/ctoken/{address}/rate-curve [get]
+func QueryCTokenRateCurve(ctx *fiber.Ctx) error {
+	// get block number from cache
+	blockNumber, err := GetBlockNumber()
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.BlockNumber)
+	}
+
+	// parse address
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	// get cToken json string from cache
+	cTokenString, err := config.RDB.HGet(context.Background(), config.ProcessedCTokensMap, config.GetConfiguredAddress(addresses.Evm)).Result()
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.ProcessedCTokensMap)
+	}
+
+	// unmarhsall cToken
+	var cToken queryengine.ProcessedCToken
+	json.Unmarshal([]byte(cTokenString), &cToken)
+	if cToken.InterestRateModel == nil {
+		return ctx.Status(StatusNotFound.Code).SendString(fmt.Sprintf("interest rate model for ctoken: %s not found", cToken.Address))
+	}
+
+	// sample the rate curve with the block time the cToken rates were annualized with
+	secondsPerBlock, err := strconv.ParseFloat(cToken.SecondsPerBlock, 64)
+	if err != nil {
+		secondsPerBlock = queryengine.DefaultSecondsPerBlock
+	}
+	curve, err := queryengine.GetRateCurve(*cToken.InterestRateModel, cToken.ReserveFactor, secondsPerBlock, queryengine.RateCurveStep)
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+
+	// generate json result string
+	result := queryengine.ResultToString(map[string]interface{}{
+		"block": blockNumber,
+		"results": map[string]interface{}{
+			"address":           cToken.Address,
+			"utilization":       cToken.Utilization,
+			"reserveFactor":     cToken.ReserveFactor,
+			"interestRateModel": cToken.InterestRateModel,
+			"curve":             curve,
+		},
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
+// QueryPrices godoc
+// @Summary      Query usd prices of all tokens
+// @Description  return json array of usd prices of every token in the token list with their price source, tokens without a price have source "none"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /prices [get]
+func QueryPrices(ctx *fiber.Ctx) error {
+	// get token prices json string from cache
+	pricesString, err := GetStoreValueFromKey(config.TokenPrices)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.TokenPrices)
+	}
+
+	return ctx.Status(StatusOkay).SendString(pricesString)
+}
+
+// QueryPriceByToken godoc
+// @Summary      Query usd price of a token
+// @Description  return json object of usd price of a token with its price source
+// @Accept       json
+// @Produce      json
+// @Param        token path string true "token address"
+// @Success      200  {object}  string
+// @Router       /prices/{token} [get]
+func QueryPriceByToken(ctx *fiber.Ctx) error {
+	// get block number from cache
+	blockNumber, err := GetBlockNumber()
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.BlockNumber)
+	}
+
+	// parse address
+	addresses, err := ParseAddress(ctx.Params("token"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	// get token price json string from cache
+	priceString, err := config.RDB.HGet(context.Background(), config.TokenPricesMap, config.GetConfiguredAddress(addresses.Evm)).Result()
+	if err != nil {
+		return ctx.Status(StatusNotFound.Code).SendString(fmt.Sprintf("price for token: %s not found", addresses.Evm))
+	}
+
+	// unmarhsall token price
+	var price queryengine.TokenPrice
+	json.Unmarshal([]byte(priceString), &price)
+
+	// generate json result string
+	result := queryengine.ResultToString(map[string]interface{}{
+		"block":   blockNumber,
+		"results": price,
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
+// QueryStats godoc
+// @Summary      Query protocol stats
+// @Description  return json object of protocol tvl, lending totals and average apys
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /stats [get]
+func QueryStats(ctx *fiber.Ctx) error {
+	// get stats json string from cache
+	statsString, err := GetStoreValueFromKey(config.ProtocolStats)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.ProtocolStats)
+	}
+
+	return ctx.Status(StatusOkay).SendString(statsString)
+}
+
+// QueryStatsHistory godoc
+// @Summary      Query protocol stats history
+// @Description  return json object of a page of the hourly protocol stats, oldest first, with the total number of entries
+// @Accept       json
+// @Produce      json
+// @Param        offset query int false "index of the first entry of the page"
+// @Param        limit query int false "number of entries per page"
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /stats/history [get]
+func QueryStatsHistory(ctx *fiber.Ctx) error {
+	offset, limit, err := ParseOffsetParams(ctx)
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	val, total, err := GetListPageFromKey(config.ProtocolStatsHistory, offset, limit)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.ProtocolStatsHistory)
+	}
+	// generate json result string
+	result := queryengine.ResultToString(map[string]interface{}{
+		"results": json.RawMessage(val),
+		"total":   total,
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
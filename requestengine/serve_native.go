@@ -2,17 +2,135 @@ package requestengine
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"althea-api/config"
 	nativequeryengine "althea-api/queryengine/native"
 
 	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/codes"
 )
 
+// QueryChainStatus godoc
+// @Summary      Query chain status
+// @Description  return json object of chain level overview (blocks, inflation, supply, staking and community pool)
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /chain/status [get]
+func QueryChainStatus(ctx *fiber.Ctx) error {
+	val, err := GetStoreValueFromKey(config.ChainStatus)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.ChainStatus)
+	}
+	return ctx.Status(StatusOkay).SendString(val)
+}
+
+// QueryAddress godoc
+// @Summary      Convert an address
+// @Description  return json object of the account, validator operator and EVM forms of a bech32 or 0x address
+// @Accept       json
+// @Produce      json
+// @Param        address path string true "bech32 or 0x address"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  string
+// @Router       /address/{address} [get]
+func QueryAddress(ctx *fiber.Ctx) error {
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(addresses)
+}
+
+// QueryBalancesByAddress godoc
+// @Summary      Query balances by account address
+// @Description  return json object of all bank balances of an account with their display units, ERC20 pairs and the ERC20 balances of the account's EVM address
+// @Accept       json
+// @Produce      json
+// @Param        address path string true "account address (bech32 or 0x)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  string
+// @Router       /bank/balances/{address} [get]
+func QueryBalancesByAddress(ctx *fiber.Ctx) error {
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	address := addresses.Account
+	// amounts are converted to display units with the denom metadata cached every tick
+	metadata, err := GetDenomsMetadataFromCache()
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.BankDenoms)
+	}
+	nqe := nativequeryengine.NewNativeQueryEngine()
+	balances, err := nativequeryengine.FetchAccountBalances(context.Background(), nqe.BankQueryHandler, nqe.Erc20QueryHandler, address, metadata)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("Failed to fetch balances for address: %s, error: %v", address, err),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(balances)
+}
+
+// QuerySupply godoc
+// @Summary      Query total supply
+// @Description  return json array of the total supply of all denoms with their display units and ERC20 pairs
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /bank/supply [get]
+func QuerySupply(ctx *fiber.Ctx) error {
+	val, err := GetStoreValueFromKey(config.BankSupply)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.BankSupply)
+	}
+	return ctx.Status(StatusOkay).SendString(val)
+}
+
+// QueryDenoms godoc
+// @Summary      Query denoms
+// @Description  return json array of denom metadata with ERC20 pairs
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /bank/denoms [get]
+func QueryDenoms(ctx *fiber.Ctx) error {
+	val, err := GetStoreValueFromKey(config.BankDenoms)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.BankDenoms)
+	}
+	return ctx.Status(StatusOkay).SendString(val)
+}
+
+// QueryTokenPairs godoc
+// @Summary      Query ERC20 token pairs
+// @Description  return json object of a page of erc20 module token pairs with token list metadata, pass next_key (base64) as key to get the next page
+// @Accept       json
+// @Produce      json
+// @Param        key query string false "base64 encoded next key of the previous page"
+// @Param        limit query int false "number of token pairs per page"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  string
+// @Router       /erc20/token-pairs [get]
+func QueryTokenPairs(ctx *fiber.Ctx) error {
+	pageKey, limit, err := ParsePageParams(ctx)
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	tokenPairs, err := nativequeryengine.GetTokenPairsPage(context.Background(), nativequeryengine.NewNativeQueryEngine().Erc20QueryHandler, pageKey, limit, config.FPIConfig.Tokens)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("Failed to fetch token pairs, error: %v", err),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(tokenPairs)
+}
+
 // QueryStakingAPR godoc
 // @Summary      Query current staking APR
-// @Description  return string of current staking APR
+// @Description  return string of current staking APR, net of community tax
 // @Accept       json
 // @Produce      json
 // @Success      200  {object}  string
@@ -46,17 +164,167 @@ func QueryValidators(ctx *fiber.Ctx) error {
 // @Description  return json object of validator
 // @Accept       json
 // @Produce      json
-// @Param        address path string true "validator address"
+// @Param        address path string true "validator address (bech32 or 0x)"
 // @Success      200  {object}  string
 // @Router       /staking/validators/{address} [get]
 func QueryValidatorByAddress(ctx *fiber.Ctx) error {
-	err := CheckValidatorAddress(ctx.Params("address"))
+	addresses, err := ParseAddress(ctx.Params("address"))
 	if err != nil {
 		return InvalidParameters(ctx, err)
 	}
-	val, err := config.RDB.HGet(context.Background(), config.ValidatorMap, ctx.Params("address")).Result()
+	val, err := config.RDB.HGet(context.Background(), config.ValidatorMap, addresses.Valoper).Result()
 	if err != nil {
-		return RedisKeyNotFound(ctx, fmt.Sprintf("validator address: %s ", ctx.Params("address")))
+		return RedisKeyNotFound(ctx, fmt.Sprintf("validator address: %s ", addresses.Valoper))
+	}
+	// generate json result string
+	result := nativequeryengine.GeneralResultToString(map[string]interface{}{
+		"results": val,
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
+// QueryValidatorDelegations godoc
+// @Summary      Query delegations to a validator
+// @Description  return json list of delegations to a validator, sorted by amount
+// @Accept       json
+// @Produce      json
+// @Param        address path string true "validator address (bech32 or 0x)"
+// @Success      200  {object}  string
+// @Router       /staking/validators/{address}/delegations [get]
+func QueryValidatorDelegations(ctx *fiber.Ctx) error {
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	val, err := config.RDB.HGet(context.Background(), config.ValidatorDelegationsMap, addresses.Valoper).Result()
+	if err != nil {
+		return RedisKeyNotFound(ctx, fmt.Sprintf("delegations for validator address: %s ", addresses.Valoper))
+	}
+	// generate json result string
+	result := nativequeryengine.GeneralResultToString(map[string]interface{}{
+		"results": val,
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
+// QueryValidatorUnbondings godoc
+// @Summary      Query unbonding delegations from a validator
+// @Description  return json list of unbonding delegation entries from a validator, sorted by amount
+// @Accept       json
+// @Produce      json
+// @Param        address path string true "validator address (bech32 or 0x)"
+// @Success      200  {object}  string
+// @Router       /staking/validators/{address}/unbondings [get]
+func QueryValidatorUnbondings(ctx *fiber.Ctx) error {
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	val, err := config.RDB.HGet(context.Background(), config.ValidatorUnbondingsMap, addresses.Valoper).Result()
+	if err != nil {
+		return RedisKeyNotFound(ctx, fmt.Sprintf("unbondings for validator address: %s ", addresses.Valoper))
+	}
+	// generate json result string
+	result := nativequeryengine.GeneralResultToString(map[string]interface{}{
+		"results": val,
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
+// QueryValidatorEvents godoc
+// @Summary      Query validator events
+// @Description  return json list of jailing, unjailing, tombstone, status and commission change events of validators, oldest first, height and time are of the block the change was detected at
+// @Accept       json
+// @Produce      json
+// @Param        validator query string false "validator address (bech32 or 0x)"
+// @Param        type query string false "event type (JAILED, UNJAILED, TOMBSTONED, STATUS_CHANGED, COMMISSION_CHANGED)"
+// @Success      200  {object}  string
+// @Failure      400  {object}  string
+// @Router       /staking/events [get]
+func QueryValidatorEvents(ctx *fiber.Ctx) error {
+	validator := ""
+	if ctx.Query("validator") != "" {
+		addresses, err := ParseAddress(ctx.Query("validator"))
+		if err != nil {
+			return InvalidParameters(ctx, err)
+		}
+		validator = addresses.Valoper
+	}
+	eventType := ctx.Query("type")
+	if eventType != "" && !nativequeryengine.IsValidatorEventType(eventType) {
+		return InvalidParameters(ctx, fmt.Errorf("invalid event type: %s", eventType))
+	}
+	val, err := GetListFromKey(config.ValidatorEvents)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.ValidatorEvents)
+	}
+	var events []nativequeryengine.ValidatorEvent
+	err = json.Unmarshal([]byte(val), &events)
+	if err != nil {
+		return ctx.Status(StatusInternalServerError.Code).SendString(fmt.Sprintf("failed to decode validator events: %v", err))
+	}
+	// generate json result string
+	result := nativequeryengine.GeneralResultToString(map[string]interface{}{
+		"results": nativequeryengine.GeneralResultToString(nativequeryengine.FilterValidatorEvents(events, validator, eventType)),
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
+// QueryCommunityPool godoc
+// @Summary      Query community pool
+// @Description  return json array of the community pool balance with display units
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /distribution/community-pool [get]
+func QueryCommunityPool(ctx *fiber.Ctx) error {
+	val, err := GetStoreValueFromKey(config.CommunityPool)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.CommunityPool)
+	}
+	return ctx.Status(StatusOkay).SendString(val)
+}
+
+// QueryValidatorCommission godoc
+// @Summary      Query validator commission
+// @Description  return json array of the accumulated commission of a validator with display units
+// @Accept       json
+// @Produce      json
+// @Param        address path string true "validator address (bech32 or 0x)"
+// @Success      200  {object}  string
+// @Router       /distribution/validators/{address}/commission [get]
+func QueryValidatorCommission(ctx *fiber.Ctx) error {
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	val, err := config.RDB.HGet(context.Background(), config.ValidatorCommissionMap, addresses.Valoper).Result()
+	if err != nil {
+		return RedisKeyNotFound(ctx, fmt.Sprintf("commission for validator address: %s ", addresses.Valoper))
+	}
+	// generate json result string
+	result := nativequeryengine.GeneralResultToString(map[string]interface{}{
+		"results": val,
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
+// QueryValidatorOutstandingRewards godoc
+// @Summary      Query validator outstanding rewards
+// @Description  return json array of the outstanding rewards of a validator with display units
+// @Accept       json
+// @Produce      json
+// @Param        address path string true "validator address (bech32 or 0x)"
+// @Success      200  {object}  string
+// @Router       /distribution/validators/{address}/outstanding-rewards [get]
+func QueryValidatorOutstandingRewards(ctx *fiber.Ctx) error {
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	val, err := config.RDB.HGet(context.Background(), config.ValidatorOutstandingRewardsMap, addresses.Valoper).Result()
+	if err != nil {
+		return RedisKeyNotFound(ctx, fmt.Sprintf("outstanding rewards for validator address: %s ", addresses.Valoper))
 	}
 	// generate json result string
 	result := nativequeryengine.GeneralResultToString(map[string]interface{}{
@@ -104,9 +372,104 @@ func QueryCSRByID(ctx *fiber.Ctx) error {
 	return ctx.Status(StatusOkay).SendString(result)
 }
 
+// QueryGovParams godoc
+// @Summary      Query governance params
+// @Description  return json object of deposit, voting and tallying params, periods are in seconds and quorum, threshold and veto threshold are in percent
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /gov/params [get]
+func QueryGovParams(ctx *fiber.Ctx) error {
+	val, err := GetStoreValueFromKey(config.GovParams)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.GovParams)
+	}
+	return ctx.Status(StatusOkay).SendString(val)
+}
+
+// QueryCSRByContract godoc
+// @Summary      Query CSR by contract address
+// @Description  return json object of the CSR a contract is registered to
+// @Accept       json
+// @Produce      json
+// @Param        address path string true "contract address (bech32 or 0x)"
+// @Success      200  {object}  string
+// @Router       /csr/contract/{address} [get]
+func QueryCSRByContract(ctx *fiber.Ctx) error {
+	addresses, err := ParseAddress(ctx.Params("address"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	address := addresses.Evm
+	csr, err := nativequeryengine.GetCSRByContract(context.Background(), nativequeryengine.NewNativeQueryEngine().CSRQueryHandler, address)
+	if err != nil {
+		return ctx.Status(StatusNotFound.Code).SendString(fmt.Sprintf("csr for contract: %s not found", address))
+	}
+	// generate json result string
+	result := nativequeryengine.GeneralResultToString(map[string]interface{}{
+		"results": nativequeryengine.GeneralResultToString(csr),
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
+// QueryCSRParams godoc
+// @Summary      Query CSR params
+// @Description  return json object of CSR module params
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /csr/params [get]
+func QueryCSRParams(ctx *fiber.Ctx) error {
+	val, err := GetStoreValueFromKey(config.CSRParams)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.CSRParams)
+	}
+	return ctx.Status(StatusOkay).SendString(val)
+}
+
+// QueryTurnstile godoc
+// @Summary      Query turnstile address
+// @Description  return string of the turnstile contract address
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  string
+// @Router       /csr/turnstile [get]
+func QueryTurnstile(ctx *fiber.Ctx) error {
+	val, err := GetStoreValueFromKey(config.CSRTurnstile)
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.CSRTurnstile)
+	}
+	return ctx.Status(StatusOkay).SendString(val)
+}
+
+// QueryCSRRevenueHistory godoc
+// @Summary      Query CSR revenue history
+// @Description  return json list of revenue earned by a CSR NFT per tick
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "CSR nft id"
+// @Success      200  {object}  string
+// @Router       /csr/{id}/revenue [get]
+func QueryCSRRevenueHistory(ctx *fiber.Ctx) error {
+	err := CheckIdString(ctx.Params("id"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	key := fmt.Sprintf("%s:%s", config.CSRRevenueHistory, ctx.Params("id"))
+	val, err := GetListFromKey(key)
+	if err != nil {
+		return RedisKeyNotFound(ctx, key)
+	}
+	// generate json result string
+	result := nativequeryengine.GeneralResultToString(map[string]interface{}{
+		"results": val,
+	})
+	return ctx.Status(StatusOkay).SendString(result)
+}
+
 // QueryProposals godoc
 // @Summary      Query proposal list
-// @Description  return json list of proposals
+// @Description  return json list of proposals, tally status percentages, quorum and thresholds are in percent
 // @Accept       json
 // @Produce      json
 // @Success      200  {object}  string
@@ -121,7 +484,7 @@ func QueryProposals(ctx *fiber.Ctx) error {
 
 // QueryProposals godoc
 // @Summary      Query proposal by id
-// @Description  return json object of proposal
+// @Description  return json object of proposal, tally status percentages, quorum and thresholds are in percent
 // @Accept       json
 // @Produce      json
 // @Param        id path string true "proposal id"
@@ -143,20 +506,119 @@ func QueryProposalByID(ctx *fiber.Ctx) error {
 	return ctx.Status(StatusOkay).SendString(result)
 }
 
+// QueryProposalVotes godoc
+// @Summary      Query votes on a proposal
+// @Description  return a page of votes on a proposal and the key of the next page
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "proposal id"
+// @Param        key query string false "base64 next key from the previous page"
+// @Param        limit query int false "number of votes per page"
+// @Success      200  {object}  map[string]interface{}
+// @Router       /gov/proposals/{id}/votes [get]
+func QueryProposalVotes(ctx *fiber.Ctx) error {
+	proposalId, err := ParseIdString(ctx.Params("id"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	pageKey, limit, err := ParsePageParams(ctx)
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	votes, err := nativequeryengine.GetProposalVotes(context.Background(), nativequeryengine.NewNativeQueryEngine().GovQueryHandler, proposalId, pageKey, limit)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("Failed to fetch votes for proposal id: %d, error: %v", proposalId, err),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(votes)
+}
+
+// QueryProposalDeposits godoc
+// @Summary      Query deposits on a proposal
+// @Description  return json list of deposits on a proposal
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "proposal id"
+// @Success      200  {object}  map[string]interface{}
+// @Router       /gov/proposals/{id}/deposits [get]
+func QueryProposalDeposits(ctx *fiber.Ctx) error {
+	proposalId, err := ParseIdString(ctx.Params("id"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	// amounts are converted to display units with the denom metadata cached every tick
+	metadata, err := GetDenomsMetadataFromCache()
+	if err != nil {
+		return RedisKeyNotFound(ctx, config.BankDenoms)
+	}
+	nqe := nativequeryengine.NewNativeQueryEngine()
+	deposits, err := nativequeryengine.GetProposalDeposits(context.Background(), nqe.GovQueryHandler, proposalId, metadata)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("Failed to fetch deposits for proposal id: %d, error: %v", proposalId, err),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(deposits)
+}
+
+// QueryProposalVote godoc
+// @Summary      Query a voter's vote on a proposal
+// @Description  return json object of the vote cast by a voter on a proposal
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "proposal id"
+// @Param        voter path string true "voter address (bech32 or 0x)"
+// @Success      200  {object}  map[string]interface{}
+// @Router       /gov/proposals/{id}/vote/{voter} [get]
+func QueryProposalVote(ctx *fiber.Ctx) error {
+	proposalId, err := ParseIdString(ctx.Params("id"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	addresses, err := ParseAddress(ctx.Params("voter"))
+	if err != nil {
+		return InvalidParameters(ctx, err)
+	}
+	voter := addresses.Account
+	vote, err := nativequeryengine.GetProposalVote(context.Background(), nativequeryengine.NewNativeQueryEngine().GovQueryHandler, proposalId, voter)
+	if err != nil {
+		// the gov module returns NotFound for a missing proposal and InvalidArgument for a voter
+		// that has not voted, the voter address itself is already validated above
+		switch GrpcErrorCode(err) {
+		case codes.NotFound, codes.InvalidArgument:
+			return ctx.Status(StatusNotFound.Code).SendString(fmt.Sprintf("vote by %s on proposal id: %d not found", voter, proposalId))
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("Failed to fetch vote by %s on proposal id: %d, error: %v", voter, proposalId, err),
+		})
+	}
+	return ctx.Status(fiber.StatusOK).JSON(vote)
+}
 
 // QueryDelegationsByAddress godoc
 // @Summary      Query delegations by delegator address
-// @Description  return json object of delegations for a given delegator address
+// @Description  return json object of delegations, unbondings, redelegations, rewards and liquid balance for a given delegator address
 // @Accept       json
 // @Produce      json
-// @Param        address path string true "delegator address"
+// @Param        address path string true "delegator address (bech32 or 0x)"
 // @Success      200  {object}  map[string]interface{}
 // @Router       /staking/delegations/{address} [get]
 func QueryDelegationsByAddress(ctx *fiber.Ctx) error {
-    delegatorAddress := ctx.Params("address")
-    
+    addresses, err := ParseAddress(ctx.Params("address"))
+    if err != nil {
+        return InvalidParameters(ctx, err)
+    }
+    delegatorAddress := addresses.Account
+
     // Directly fetch delegations from blockchain without using Redis cache
-    delegationsResponse, err := nativequeryengine.FetchUserDelegations(context.Background(), nativequeryengine.NewNativeQueryEngine().StakingQueryHandler, nativequeryengine.NewNativeQueryEngine().DistributionQueryHandler, delegatorAddress)
+    // amounts are converted to display units with the denom metadata cached every tick
+    metadata, err := GetDenomsMetadataFromCache()
+    if err != nil {
+        return RedisKeyNotFound(ctx, config.BankDenoms)
+    }
+    nqe := nativequeryengine.NewNativeQueryEngine()
+    delegationsResponse, err := nativequeryengine.FetchUserDelegations(context.Background(), nqe.StakingQueryHandler, nqe.DistributionQueryHandler, nqe.BankQueryHandler, delegatorAddress, metadata)
     if err != nil {
         // Handle error if fetching from blockchain fails
         return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -2,15 +2,20 @@ package requestengine
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"althea-api/config"
+	nativequeryengine "althea-api/queryengine/native"
 
-	cantoConfig "github.com/Canto-Network/Canto/v6/cmd/config"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -20,6 +25,11 @@ var (
 	StatusOkay                = fiber.StatusOK               // 200 (success)
 )
 
+const (
+	DefaultPageLimit = 100  // default number of items per page
+	MaxPageLimit     = 1000 // maximum number of items per page
+)
+
 // functions to return status errors
 func RedisKeyNotFound(ctx *fiber.Ctx, key string) error {
 	//key there are looking for is not in redis
@@ -32,6 +42,15 @@ func InvalidParameters(ctx *fiber.Ctx, err error) error {
 	return ctx.Status(StatusBadRequest.Code).SendString(err.Error())
 }
 
+// GrpcErrorCode returns the gRPC status code of an error, looking through wrapped errors
+func GrpcErrorCode(err error) codes.Code {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		return grpcErr.GRPCStatus().Code()
+	}
+	return status.Code(err)
+}
+
 func GetStoreValueFromKey(key string) (string, error) {
 	rdb := config.RDB
 	val, err := rdb.Get(context.Background(), key).Result()
@@ -41,6 +60,46 @@ func GetStoreValueFromKey(key string) (string, error) {
 	return val, nil
 }
 
+// GetDenomsMetadataFromCache returns the denom metadata of the denoms the native engine caches every tick
+func GetDenomsMetadataFromCache() (nativequeryengine.DenomMetadataMap, error) {
+	val, err := GetStoreValueFromKey(config.BankDenoms)
+	if err != nil {
+		return nil, err
+	}
+	var denoms []nativequeryengine.Denom
+	err = json.Unmarshal([]byte(val), &denoms)
+	if err != nil {
+		return nil, err
+	}
+	return nativequeryengine.DenomsToMetadata(denoms), nil
+}
+
+// GetListFromKey returns the entries of a cached history list as a json array string
+// a list with no entries yet is returned as an empty json array
+func GetListFromKey(key string) (string, error) {
+	rdb := config.RDB
+	vals, err := rdb.LRange(context.Background(), key, 0, -1).Result()
+	if err != nil {
+		return "", err
+	}
+	return "[" + strings.Join(vals, ",") + "]", nil
+}
+
+// GetListPageFromKey returns limit entries of a list starting at offset as a json array string,
+// along with the total number of entries in the list
+func GetListPageFromKey(key string, offset int64, limit int64) (string, int64, error) {
+	rdb := config.RDB
+	total, err := rdb.LLen(context.Background(), key).Result()
+	if err != nil {
+		return "", 0, err
+	}
+	vals, err := rdb.LRange(context.Background(), key, offset, offset+limit-1).Result()
+	if err != nil {
+		return "", 0, err
+	}
+	return "[" + strings.Join(vals, ",") + "]", total, nil
+}
+
 func GetBlockNumber() (string, error) {
 	// get block number from cache
 	blockNumber, err := GetStoreValueFromKey(config.BlockNumber)
@@ -50,12 +109,66 @@ func GetBlockNumber() (string, error) {
 	return blockNumber, nil
 }
 
-// CheckValidatorAddress checks if the given address is a valid validator address
-func CheckValidatorAddress(address string) error {
-	if !(strings.HasPrefix(address, cantoConfig.Bech32PrefixValAddr)) {
-		return fmt.Errorf("invalid bech32 validator address: %s", address)
+// ParseAddress accepts a bech32 account, bech32 validator operator or 0x address
+// and returns the address in all of its forms
+func ParseAddress(address string) (nativequeryengine.Addresses, error) {
+	return nativequeryengine.ConvertAddress(address)
+}
+
+// ParseIdString parses the given id as a uint64 id
+func ParseIdString(id string) (uint64, error) {
+	parsedId, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id: %s", id)
 	}
-	return nil
+	return parsedId, nil
+}
+
+// ParsePageParams parses the optional "key" (base64 next key) and "limit" query parameters
+func ParsePageParams(ctx *fiber.Ctx) ([]byte, uint64, error) {
+	var key []byte
+	if ctx.Query("key") != "" {
+		decodedKey, err := base64.StdEncoding.DecodeString(ctx.Query("key"))
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid page key: %s", ctx.Query("key"))
+		}
+		key = decodedKey
+	}
+	limit, err := parseLimit(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return key, limit, nil
+}
+
+// ParseOffsetParams parses the optional "offset" and "limit" query parameters of list pages
+func ParseOffsetParams(ctx *fiber.Ctx) (int64, int64, error) {
+	offset := int64(0)
+	if ctx.Query("offset") != "" {
+		parsedOffset, err := strconv.ParseInt(ctx.Query("offset"), 10, 64)
+		if err != nil || parsedOffset < 0 {
+			return 0, 0, fmt.Errorf("invalid offset: %s", ctx.Query("offset"))
+		}
+		offset = parsedOffset
+	}
+	limit, err := parseLimit(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return offset, int64(limit), nil
+}
+
+// parseLimit parses the optional "limit" query parameter, bounded by MaxPageLimit
+func parseLimit(ctx *fiber.Ctx) (uint64, error) {
+	limit := uint64(DefaultPageLimit)
+	if ctx.Query("limit") != "" {
+		parsedLimit, err := strconv.ParseUint(ctx.Query("limit"), 10, 64)
+		if err != nil || parsedLimit == 0 || parsedLimit > MaxPageLimit {
+			return 0, fmt.Errorf("invalid limit: %s", ctx.Query("limit"))
+		}
+		limit = parsedLimit
+	}
+	return limit, nil
 }
 
 // CheckIdString checks if the given id is a valid string uint64 id
@@ -0,0 +1,157 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"althea-api/config"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// a notification is only sent once per webhook and key within this period
+	dedupeTTL = 30 * 24 * time.Hour
+	// number of attempts to deliver a notification, the delay doubles after each attempt
+	maxAttempts = 4
+	retryDelay  = 2 * time.Second
+)
+
+// Notification is the payload POSTed to a webhook
+type Notification struct {
+	Webhook string `json:"webhook"`
+	Rule    string `json:"rule"`
+	// key identifies the condition that fired, a notification is only sent once per webhook and key
+	Key  string      `json:"key"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+// Match is a configured rule along with the webhook it belongs to
+type Match struct {
+	Webhook config.Webhook
+	Rule    config.WebhookRule
+}
+
+// Dispatcher sends notifications to configured webhooks
+type Dispatcher struct {
+	redisclient *redis.Client
+	httpclient  *http.Client
+	webhooks    []config.Webhook
+	retryDelay  time.Duration
+}
+
+// Returns a Dispatcher for the webhooks in config
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		redisclient: config.RDB,
+		httpclient:  &http.Client{Timeout: 10 * time.Second},
+		webhooks:    config.Webhooks,
+		retryDelay:  retryDelay,
+	}
+}
+
+// Rules returns every configured rule of the given type
+func (d *Dispatcher) Rules(ruleType string) []Match {
+	matches := []Match{}
+	for _, webhook := range d.webhooks {
+		for _, rule := range webhook.Rules {
+			if rule.Type == ruleType {
+				matches = append(matches, Match{Webhook: webhook, Rule: rule})
+			}
+		}
+	}
+	return matches
+}
+
+// Notify sends a notification for a fired rule unless one was already sent for the same webhook and key.
+// Delivery runs in the background so engine ticks are not held up by slow webhooks.
+func (d *Dispatcher) Notify(ctx context.Context, match Match, key string, data interface{}) {
+	dedupeKey := fmt.Sprintf("%s:%s:%s", config.WebhookSent, match.Webhook.Name, key)
+	isNew, err := d.redisclient.SetNX(ctx, dedupeKey, time.Now().Unix(), dedupeTTL).Result()
+	if err != nil {
+		log.Error().Err(err).Str("func", "Notify").Msgf("Failed to dedupe notification %s for webhook %s", key, match.Webhook.Name)
+		return
+	}
+	if !isNew {
+		return
+	}
+	notification := Notification{
+		Webhook: match.Webhook.Name,
+		Rule:    match.Rule.Type,
+		Key:     key,
+		Time:    time.Now().UTC(),
+		Data:    data,
+	}
+	go func() {
+		err := d.Deliver(ctx, match.Webhook, notification)
+		if err != nil {
+			log.Error().Err(err).Str("func", "Notify").Msgf("Failed to deliver notification %s to webhook %s", key, match.Webhook.Name)
+			// forget the notification so it is sent again if the condition still holds next tick
+			d.redisclient.Del(ctx, dedupeKey)
+		}
+	}()
+}
+
+// Deliver POSTs a signed notification to a webhook, retrying failed attempts
+func (d *Dispatcher) Deliver(ctx context.Context, webhook config.Webhook, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	delay := d.retryDelay
+	for attempt := 1; ; attempt++ {
+		err = d.post(ctx, webhook, body)
+		if err == nil {
+			return nil
+		}
+		if attempt == maxAttempts {
+			return fmt.Errorf("failed after %d attempts: %w", attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (d *Dispatcher) post(ctx context.Context, webhook config.Webhook, body []byte) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", Sign(webhook.Secret(), timestamp, body))
+	resp, err := d.httpclient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New("unexpected status " + resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret.
+// Receivers recompute it from the X-Webhook-Timestamp header and the raw body to verify a payload.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
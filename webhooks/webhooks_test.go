@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"althea-api/config"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		want      string
+	}{
+		{
+			name:      "empty body",
+			secret:    "secret",
+			timestamp: "1700000000",
+			body:      []byte{},
+			want:      "4bc5f74d868b97888288889c5d9d65df02526f94c1592a79fdf4fe8b26e311e5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, tt.body); got != tt.want {
+				t.Errorf("Sign() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	t.Setenv("TEST_WEBHOOK_SECRET", "secret")
+	tests := []struct {
+		name         string
+		failAttempts int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "delivered on first attempt",
+			failAttempts: 0,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retried until delivered",
+			failAttempts: 2,
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			failAttempts: maxAttempts,
+			wantAttempts: maxAttempts,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				body, _ := io.ReadAll(r.Body)
+				if r.Header.Get("X-Webhook-Signature") != Sign("secret", r.Header.Get("X-Webhook-Timestamp"), body) {
+					t.Errorf("invalid signature")
+				}
+				var notification Notification
+				if err := json.Unmarshal(body, &notification); err != nil || notification.Key != "proposal:1" {
+					t.Errorf("unexpected payload: %s", body)
+				}
+				if attempts <= tt.failAttempts {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			d := &Dispatcher{httpclient: server.Client(), retryDelay: time.Millisecond}
+			webhook := config.Webhook{Name: "test", URL: server.URL, SecretEnv: "TEST_WEBHOOK_SECRET"}
+			err := d.Deliver(context.Background(), webhook, Notification{Webhook: "test", Rule: config.RuleProposalVoting, Key: "proposal:1"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Deliver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("Deliver() attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
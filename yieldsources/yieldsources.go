@@ -0,0 +1,180 @@
+package yieldsources
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"althea-api/config"
+)
+
+const (
+	TypeRoundDetails = "roundDetails"
+	TypeFixed        = "fixed"
+)
+
+// YieldSource computes the supply apy of cTokens whose yield comes from outside the lending market
+type YieldSource interface {
+	// Calls returns the view calls the source needs for a cToken, results are stored under the cToken
+	Calls(cToken config.Token) []config.Contract
+	// SupplyAPY returns the supply apy in percent from the multicall results of a cToken
+	SupplyAPY(results map[string][]interface{}) (float64, error)
+}
+
+// constructors of each source type
+var sourceTypes = map[string]func(tag string, source config.YieldSource) (YieldSource, error){
+	TypeRoundDetails: newRoundDetailsSource,
+	TypeFixed:        newFixedSource,
+}
+
+// New returns the yield source for a tag configured in the token list
+func New(tag string, source config.YieldSource) (YieldSource, error) {
+	constructor, ok := sourceTypes[source.Type]
+	if !ok {
+		return nil, fmt.Errorf("yieldsources::New - unknown type %q for tag %s", source.Type, tag)
+	}
+	return constructor(tag, source)
+}
+
+// NewFromConfig returns the yield sources of all tags configured in the token list
+func NewFromConfig(sources map[string]config.YieldSource) (map[string]YieldSource, error) {
+	yieldSources := make(map[string]YieldSource, len(sources))
+	for tag, source := range sources {
+		yieldSource, err := New(tag, source)
+		if err != nil {
+			return nil, err
+		}
+		yieldSources[tag] = yieldSource
+	}
+	return yieldSources, nil
+}
+
+// Calls returns the view calls of the yield sources of all tagged cTokens
+func Calls(sources map[string]YieldSource, cTokens []config.Token) []config.Contract {
+	calls := []config.Contract{}
+	for _, cToken := range cTokens {
+		for _, tag := range cToken.Tags {
+			if source, ok := sources[tag]; ok {
+				calls = append(calls, source.Calls(cToken)...)
+			}
+		}
+	}
+	return calls
+}
+
+// ROUND DETAILS
+
+// roundDetailsSource reads the latest balance and interest reported by an oracle and annualizes it
+type roundDetailsSource struct {
+	key       string
+	oracle    string
+	feeOffset float64
+	holidays  map[string]float64
+}
+
+func newRoundDetailsSource(tag string, source config.YieldSource) (YieldSource, error) {
+	if source.Oracle == "" {
+		return nil, errors.New("yieldsources::newRoundDetailsSource - missing oracle for tag " + tag)
+	}
+	for date := range source.Holidays {
+		if _, err := time.Parse("01-02", date); err != nil {
+			return nil, fmt.Errorf("yieldsources::newRoundDetailsSource - invalid holiday %q for tag %s", date, tag)
+		}
+	}
+	return &roundDetailsSource{
+		key:       tag + "RoundDetails",
+		oracle:    source.Oracle,
+		feeOffset: source.FeeOffset,
+		holidays:  source.Holidays,
+	}, nil
+}
+
+func (s *roundDetailsSource) Calls(cToken config.Token) []config.Contract {
+	return []config.Contract{
+		{
+			Name:    cToken.Symbol + s.key,
+			Address: s.oracle,
+			Keys: []string{
+				"cTokens:" + cToken.Address + ":" + s.key,
+			},
+			Methods: []string{
+				"latestRoundDetails()(uint80,uint256,uint256,uint256,uint256)",
+			},
+			Args: [][]interface{}{
+				{},
+			},
+		},
+	}
+}
+
+func (s *roundDetailsSource) SupplyAPY(results map[string][]interface{}) (float64, error) {
+	details := results[s.key]
+	if len(details) < 5 {
+		return 0, errors.New("yieldsources::SupplyAPY - missing round details")
+	}
+	balance, ok1 := toBigInt(details[1])
+	interest, ok2 := toBigInt(details[2])
+	updatedAt, ok3 := toBigInt(details[4])
+	if !ok1 || !ok2 || !ok3 {
+		return 0, errors.New("yieldsources::SupplyAPY - invalid round details")
+	}
+	return RoundDetailsAPY(balance, interest, updatedAt.Int64(), s.holidays) - s.feeOffset, nil
+}
+
+// InterestDaysPassed returns the days of interest covered by a report, reports made on weekends cover three days
+// and reports made on holidays cover the configured number of days
+func InterestDaysPassed(updatedAt int64, holidays map[string]float64) float64 {
+	// get time from UNIX timestamp
+	unixTime := time.Unix(updatedAt, 0).UTC()
+	// check for holidays
+	if days := holidays[unixTime.Format("01-02")]; days != 0 {
+		return days
+	}
+	// check if weekend reporting (for three days)
+	if unixTime.Weekday() == time.Saturday || unixTime.Weekday() == time.Sunday {
+		return 3
+	}
+	return 1
+}
+
+// RoundDetailsAPY annualizes the interest reported on the balance over the days it covers
+func RoundDetailsAPY(balance *big.Int, interest *big.Int, updatedAt int64, holidays map[string]float64) float64 {
+	prevBalance := new(big.Float).Sub(new(big.Float).SetInt(balance), new(big.Float).SetInt(interest))
+	if prevBalance.Sign() <= 0 {
+		return 0
+	}
+	spotAPY, _ := new(big.Float).Quo(new(big.Float).SetInt(interest), prevBalance).Float64()
+	return (spotAPY * 365 * 100) / InterestDaysPassed(updatedAt, holidays)
+}
+
+// FIXED
+
+// fixedSource reports a constant apy
+type fixedSource struct {
+	rate float64
+}
+
+func newFixedSource(tag string, source config.YieldSource) (YieldSource, error) {
+	if source.FixedRate <= 0 {
+		return nil, errors.New("yieldsources::newFixedSource - missing fixed rate for tag " + tag)
+	}
+	return &fixedSource{rate: source.FixedRate}, nil
+}
+
+func (s *fixedSource) Calls(cToken config.Token) []config.Contract {
+	return nil
+}
+
+func (s *fixedSource) SupplyAPY(results map[string][]interface{}) (float64, error) {
+	return s.rate, nil
+}
+
+// multicall results hold uint values as decimal strings
+func toBigInt(value interface{}) (*big.Int, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Int).SetString(str, 10)
+}
@@ -0,0 +1,137 @@
+package yieldsources
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"althea-api/config"
+)
+
+var testHolidays = map[string]float64{"12-30": 4}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  config.YieldSource
+		wantErr bool
+	}{
+		{name: "round details", source: config.YieldSource{Type: TypeRoundDetails, Oracle: "0x1d18c02bC80b1921255E71cF2939C03258d75470", Holidays: testHolidays}},
+		{name: "round details without oracle", source: config.YieldSource{Type: TypeRoundDetails}, wantErr: true},
+		{name: "round details with invalid holiday", source: config.YieldSource{Type: TypeRoundDetails, Oracle: "0x1d18c02bC80b1921255E71cF2939C03258d75470", Holidays: map[string]float64{"30-12": 4}}, wantErr: true},
+		{name: "fixed", source: config.YieldSource{Type: TypeFixed, FixedRate: 4.9}},
+		{name: "fixed without rate", source: config.YieldSource{Type: TypeFixed}, wantErr: true},
+		{name: "unknown type", source: config.YieldSource{Type: "unknown"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New("tag", tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalls(t *testing.T) {
+	sources, err := NewFromConfig(map[string]config.YieldSource{
+		"hashnote": {Type: TypeRoundDetails, Oracle: "0x1d18c02bC80b1921255E71cF2939C03258d75470"},
+		"fbill":    {Type: TypeFixed, FixedRate: 4.9},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cTokens := []config.Token{
+		{Symbol: "cUSYC", Address: "0x0355E393cF0cf5486D9CAefB64407b7B1033C2f1", Tags: []string{"hashnote"}},
+		{Symbol: "cfBill", Address: "0xF1F89dF149bc5f2b6B29783915D1F9FE2d24459c", Tags: []string{"fbill"}},
+		{Symbol: "cNOTE", Address: "0xEe602429Ef7eCe0a13e4FfE8dBC16e101049504C"},
+	}
+	want := []config.Contract{
+		{
+			Name:    "cUSYChashnoteRoundDetails",
+			Address: "0x1d18c02bC80b1921255E71cF2939C03258d75470",
+			Keys:    []string{"cTokens:0x0355E393cF0cf5486D9CAefB64407b7B1033C2f1:hashnoteRoundDetails"},
+			Methods: []string{"latestRoundDetails()(uint80,uint256,uint256,uint256,uint256)"},
+			Args:    [][]interface{}{{}},
+		},
+	}
+	if got := Calls(sources, cTokens); !reflect.DeepEqual(got, want) {
+		t.Errorf("Calls() = %v, want %v", got, want)
+	}
+}
+
+func TestInterestDaysPassed(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		want float64
+	}{
+		{name: "weekday", time: time.Date(2023, time.November, 15, 12, 0, 0, 0, time.UTC), want: 1},
+		{name: "saturday", time: time.Date(2023, time.November, 18, 12, 0, 0, 0, time.UTC), want: 3},
+		{name: "sunday", time: time.Date(2023, time.November, 19, 12, 0, 0, 0, time.UTC), want: 3},
+		{name: "holiday", time: time.Date(2023, time.December, 30, 12, 0, 0, 0, time.UTC), want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InterestDaysPassed(tt.time.Unix(), testHolidays); got != tt.want {
+				t.Errorf("InterestDaysPassed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupplyAPY(t *testing.T) {
+	roundDetails, err := New("hashnote", config.YieldSource{Type: TypeRoundDetails, Oracle: "0x1d18c02bC80b1921255E71cF2939C03258d75470", FeeOffset: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := New("fbill", config.YieldSource{Type: TypeFixed, FixedRate: 4.9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	weekday := time.Date(2023, time.November, 15, 12, 0, 0, 0, time.UTC).Unix()
+
+	tests := []struct {
+		name    string
+		source  YieldSource
+		results map[string][]interface{}
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:   "round details",
+			source: roundDetails,
+			// 1 interest on a balance of 10000 reported for one day
+			results: map[string][]interface{}{
+				"hashnoteRoundDetails": {"1", "10001", "1", "0", big.NewInt(weekday).String()},
+			},
+			want: 3.65 - 0.5,
+		},
+		{
+			name:    "round details missing",
+			source:  roundDetails,
+			results: map[string][]interface{}{},
+			wantErr: true,
+		},
+		{
+			name:    "fixed",
+			source:  fixed,
+			results: map[string][]interface{}{},
+			want:    4.9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.source.SupplyAPY(tt.results)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SupplyAPY() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("SupplyAPY() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}